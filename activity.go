@@ -0,0 +1,240 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ActivityType 是用户动态的类型
+type ActivityType int
+
+const (
+	// ActivityAskQuestion 提了一个问题，Target 是 *Question
+	ActivityAskQuestion ActivityType = iota
+
+	// ActivityAnswerQuestion 回答了问题，Target 是 *Answer
+	ActivityAnswerQuestion
+
+	// ActivityUpvoteAnswer 赞同了回答，Target 是 *Answer
+	ActivityUpvoteAnswer
+
+	// ActivityFollowQuestion 关注了问题，Target 是 *Question
+	ActivityFollowQuestion
+
+	// ActivityFollowTopic 关注了话题，Target 是 *Topic
+	ActivityFollowTopic
+
+	// ActivityFollowCollection 关注了收藏夹，Target 是 *Collection
+	ActivityFollowCollection
+
+	// ActivityPublishArticle 发表了专栏文章，Target 是 *Article
+	ActivityPublishArticle
+
+	// ActivityFollowColumn 关注了专栏，Target 是 *Column
+	ActivityFollowColumn
+)
+
+// activityTypes 是动态的 data-type-detail 属性对应的类型
+var activityTypes = map[string]ActivityType{
+	"member_ask_question":    ActivityAskQuestion,
+	"member_answer_question": ActivityAnswerQuestion,
+	"member_voteup_answer":   ActivityUpvoteAnswer,
+	"member_follow_question": ActivityFollowQuestion,
+	"member_follow_topic":    ActivityFollowTopic,
+	"member_follow_favlist":  ActivityFollowCollection,
+	"member_create_article":  ActivityPublishArticle,
+	"member_follow_column":   ActivityFollowColumn,
+}
+
+// String 返回类型的名称，如 upvote_answer
+func (t ActivityType) String() string {
+	switch t {
+	case ActivityAskQuestion:
+		return "ask_question"
+	case ActivityAnswerQuestion:
+		return "answer_question"
+	case ActivityUpvoteAnswer:
+		return "upvote_answer"
+	case ActivityFollowQuestion:
+		return "follow_question"
+	case ActivityFollowTopic:
+		return "follow_topic"
+	case ActivityFollowCollection:
+		return "follow_collection"
+	case ActivityPublishArticle:
+		return "publish_article"
+	case ActivityFollowColumn:
+		return "follow_column"
+	}
+	return fmt.Sprintf("ACTIVITY(%d)", int(t))
+}
+
+// Activity 是用户的一条动态
+type Activity struct {
+	// Type 是动态的类型
+	Type ActivityType
+
+	// Time 是动态的时间
+	Time time.Time
+
+	// Target 是动态的对象，具体的类型见 ActivityType，也可以用 Question, Answer 等方法获取
+	Target interface{}
+}
+
+// Question 返回动态的问题，Target 不是 *Question 时返回 nil
+func (a *Activity) Question() *Question {
+	question, _ := a.Target.(*Question)
+	return question
+}
+
+// Answer 返回动态的回答，Target 不是 *Answer 时返回 nil
+func (a *Activity) Answer() *Answer {
+	answer, _ := a.Target.(*Answer)
+	return answer
+}
+
+// Topic 返回动态的话题，Target 不是 *Topic 时返回 nil
+func (a *Activity) Topic() *Topic {
+	topic, _ := a.Target.(*Topic)
+	return topic
+}
+
+// Collection 返回动态的收藏夹，Target 不是 *Collection 时返回 nil
+func (a *Activity) Collection() *Collection {
+	collection, _ := a.Target.(*Collection)
+	return collection
+}
+
+// Article 返回动态的专栏文章，Target 不是 *Article 时返回 nil
+func (a *Activity) Article() *Article {
+	article, _ := a.Target.(*Article)
+	return article
+}
+
+// Column 返回动态的专栏，Target 不是 *Column 时返回 nil
+func (a *Activity) Column() *Column {
+	column, _ := a.Target.(*Column)
+	return column
+}
+
+func (a *Activity) String() string {
+	return fmt.Sprintf("<Activity: %s - %s - %v>", a.Type, a.Time.Format("2006-01-02 15:04:05"), a.Target)
+}
+
+// newActivity 解析一条动态，不支持的类型或者解析失败时返回 nil
+func (c *Client) newActivity(sel *goquery.Selection) *Activity {
+	detail := activityDetail(sel)
+	activityType, ok := activityTypes[detail]
+	if !ok {
+		c.logger.Debug("忽略不支持的动态", "type", detail)
+		return nil
+	}
+
+	var target interface{}
+	switch activityType {
+	case ActivityAskQuestion, ActivityFollowQuestion:
+		a := sel.Find("a.question_link")
+		href, _ := a.Attr("href")
+		target = newQuestion(c, c.makeZhihuLink(href), strip(a.Text()))
+	case ActivityAnswerQuestion, ActivityUpvoteAnswer:
+		target = c.newAnswerFromActivity(sel)
+	case ActivityFollowTopic:
+		a := sel.Find("a.topic-link")
+		href, _ := a.Attr("href")
+		target = newTopic(c, c.makeZhihuLink(href), strip(a.Text()))
+	case ActivityFollowCollection:
+		a := sel.Find("a.collection-link")
+		href, _ := a.Attr("href")
+		target = newCollection(c, c.makeZhihuLink(href), strip(a.Text()), nil)
+	case ActivityPublishArticle:
+		a := sel.Find("a.post-link")
+		href, _ := a.Attr("href")
+		article, err := c.Article(c.columnLinkFromHref(href), strip(a.Text()))
+		if err != nil {
+			c.logger.Warn("无法识别的专栏文章链接", "href", href)
+			return nil
+		}
+		target = article
+	case ActivityFollowColumn:
+		a := sel.Find("a.column_link")
+		href, _ := a.Attr("href")
+		column, err := c.Column(c.columnLinkFromHref(href), strip(a.Text()))
+		if err != nil {
+			c.logger.Warn("无法识别的专栏链接", "href", href)
+			return nil
+		}
+		target = column
+	}
+
+	return &Activity{Type: activityType, Time: activityTime(sel), Target: target}
+}
+
+// activitiesPage 获取 cursor.Start 之前的一页动态，并把下一页的起始时间记录在 cursor.Start 里。
+// since 不为零时只返回 since 之后的动态，遇到更早的动态时不再翻页
+func (user *User) activitiesPage(ctx context.Context, cursor *Cursor, since time.Time) (*goquery.Selection, bool, error) {
+	if _, err := user.DocCtx(ctx); err != nil {
+		return nil, false, err
+	}
+
+	form := url.Values{}
+	form.Set("_xsrf", user.GetXSRF())
+	form.Set("start", strconv.FormatInt(cursor.Start, 10))
+	doc, dataNum, err := user.client.newDocByNormalAjax(ctx, urlJoin(user.Link, "/activities"), form)
+	if err != nil {
+		return nil, false, err
+	}
+
+	items := doc.Find("div.zm-profile-section-item")
+	if items.Size() == 0 {
+		return items, false, nil
+	}
+	more := dataNum == pageSize
+	cursor.Start = activityTime(items.Last()).Unix()
+
+	if !since.IsZero() {
+		total := items.Size()
+		items = items.FilterFunction(func(index int, sel *goquery.Selection) bool {
+			return !activityTime(sel).Before(since)
+		})
+		more = more && items.Size() == total
+	}
+	return items, more, nil
+}
+
+// activityDetail 返回一条动态的 data-type-detail 属性，如 member_voteup_answer
+func activityDetail(sel *goquery.Selection) string {
+	detail, _ := sel.Attr("data-type-detail")
+	return detail
+}
+
+// activityTime 返回一条动态的时间，即 data-time 属性（Unix 时间戳）
+func activityTime(sel *goquery.Selection) time.Time {
+	value, _ := sel.Attr("data-time")
+	ts, _ := strconv.ParseInt(value, 10, 64)
+	return time.Unix(ts, 0)
+}
+
+// newAnswerFromActivity 从一条动态里解析回答及其问题、答主
+func (c *Client) newAnswerFromActivity(sel *goquery.Selection) *Answer {
+	a := sel.Find("a.question_link")
+	answerHref, _ := a.Attr("href")
+	qLink := answerHref
+	if i := strings.Index(answerHref, "/answer"); i >= 0 {
+		qLink = answerHref[:i]
+	}
+	question := newQuestion(c, c.makeZhihuLink(qLink), strip(a.Text()))
+
+	author := c.newUserFromAnswerAuthorTag(sel.Find("div.zm-item-answer-author-info"))
+	answer := newAnswer(c, c.makeZhihuLink(answerHref), question, author)
+
+	voteText, _ := sel.Find("a.zm-item-vote-count").Attr("data-votecount")
+	vote, _ := strconv.Atoi(voteText)
+	answer.setUpvote(vote)
+	return answer
+}
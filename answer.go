@@ -1,10 +1,11 @@
 package zhihu
 
 import (
+	"context"
 	"fmt"
-	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -22,8 +23,12 @@ type Answer struct {
 
 // NewAnswer 用于创建一个 Answer 对象，其中 link 是必传的，question, author 可以为 nil
 func NewAnswer(link string, question *Question, author *User) *Answer {
+	return newAnswer(defaultClient, link, question, author)
+}
+
+func newAnswer(client *Client, link string, question *Question, author *User) *Answer {
 	return &Answer{
-		Page:     newZhihuPage(link),
+		Page:     newZhihuPage(client, link),
 		question: question,
 		author:   author,
 	}
@@ -51,9 +56,9 @@ func (a *Answer) GetQuestion() *Question {
 
 	doc := a.Doc()
 	href, _ := doc.Find("h2.zm-item-title>a").Attr("href")
-	link := makeZhihuLink(href)
+	link := a.client.makeZhihuLink(href)
 	title := strip(doc.Find("h2.zm-item-title").First().Text())
-	return NewQuestion(link, title)
+	return newQuestion(a.client, link, title)
 }
 
 // Author 返回该答案的作者
@@ -64,7 +69,7 @@ func (a *Answer) GetAuthor() *User {
 
 	doc := a.Doc()
 	sel := doc.Find("div.zm-item-answer-author-info").First()
-	return newUserFromAnswerAuthorTag(sel)
+	return a.client.newUserFromAnswerAuthorTag(sel)
 }
 
 // GetUpvote 返回赞同数
@@ -80,25 +85,36 @@ func (a *Answer) GetUpvote() int {
 	return upvote
 }
 
-// ToMarkdown 把回答导出到 markdown 文件
+// ToMarkdown 把回答导出到 markdown 文件，页面载入失败时返回错误，不会写入文件
 func (a *Answer) ToMarkdown(filename string) error {
 	if !strings.HasSuffix(filename, ".md") && !strings.HasSuffix(filename, ".markdown") {
 		filename += ".md"
 	}
+	if _, err := a.DocCtx(context.Background()); err != nil {
+		return err
+	}
 
-	// TODO convert to markdown
-	md := ""
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(a.GetContent()))
+	if err != nil {
+		return err
+	}
 
+	md := markdownFrontMatter(a.GetQuestion().GetTitle(), a.GetAuthor().GetUserID(), a.GetUpvote(), a.Link)
+	md += htmlToMarkdown(doc.Find("body"))
 	return saveString(filename, md)
 }
 
-// ToHtml 把网页源码导出到 html 文件
+// ToHtml 把网页源码导出到 html 文件，页面载入失败时返回错误，不会写入文件
 func (a *Answer) ToHtml(filename string) error {
 	if !strings.HasSuffix(filename, ".html") {
 		filename += ".html"
 	}
 
-	html, err := a.Doc().Html()
+	doc, err := a.DocCtx(context.Background())
+	if err != nil {
+		return err
+	}
+	html, err := doc.Html()
 	if err != nil {
 		return err
 	}
@@ -114,7 +130,7 @@ func (a *Answer) GetContent() string {
 	sel := a.Doc().Find("div#zh-question-answer-wrap").Find("div.zm-editable-content")
 	content, err := answerSelectionToHtml(sel)
 	if err != nil {
-		logger.Error("导出 HTML 失败：%s", err.Error())
+		a.client.logger.Error("导出 HTML 失败", "answer", a.Link, "err", err)
 		return ""
 	}
 	a.setField("content", content)
@@ -123,15 +139,25 @@ func (a *Answer) GetContent() string {
 
 // GetVotersN 返回 n 个点赞的用户，如果 n < 0，返回所有点赞的用户
 func (a *Answer) GetVotersN(n int) []*User {
+	voters, _ := a.GetVotersNCtx(context.Background(), n)
+	return voters
+}
+
+// GetVotersNCtx 同 GetVotersN，ctx 取消或超时后请求会被中止，并返回错误
+func (a *Answer) GetVotersNCtx(ctx context.Context, n int) ([]*User, error) {
 	if n == 0 {
-		return nil
+		return nil, nil
+	}
+
+	if _, err := a.DocCtx(ctx); err != nil {
+		return nil, err
 	}
 
 	querystring := fmt.Sprintf(`params={"answer_id":"%d"}`, a.GetID())
-	url := makeZhihuLink("/node/AnswerFullVoteInfoV2" + "?" + querystring)
-	doc, err := newDocumentFromURL(url)
+	url := a.client.makeZhihuLink("/node/AnswerFullVoteInfoV2" + "?" + querystring)
+	doc, err := a.client.newDocumentFromURL(ctx, url)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	sel := doc.Find(".voters span")
@@ -146,16 +172,16 @@ func (a *Answer) GetVotersN(n int) []*User {
 		var userLink string
 		if !(userId == "匿名用户" || userId == "知乎用户") {
 			path, _ := span.Find("a").Attr("href")
-			userLink = makeZhihuLink(path)
+			userLink = a.client.makeZhihuLink(path)
 		}
-		voters = append(voters, NewUser(userLink, userId))
+		voters = append(voters, newUser(a.client, userLink, userId))
 		if n > 0 && len(voters) == n {
 			return false
 		}
 		return true
 	})
 
-	return voters
+	return voters, nil
 }
 
 // GetVoters 返回点赞的用户
@@ -176,6 +202,27 @@ func (a *Answer) GetCommentsNum() int {
 	return rv
 }
 
+// GetComments 返回回答的所有评论
+func (a *Answer) GetComments() []*Comment {
+	return a.GetCommentsN(-1)
+}
+
+// GetCommentsN 返回前 n 条评论，如果 n < 0，返回所有评论
+func (a *Answer) GetCommentsN(n int) []*Comment {
+	comments, _ := a.GetCommentsNCtx(context.Background(), n)
+	return comments
+}
+
+// GetCommentsNCtx 同 GetCommentsN，ctx 取消或超时后停止翻页并返回错误
+func (a *Answer) GetCommentsNCtx(ctx context.Context, n int) ([]*Comment, error) {
+	return a.CommentsIter(ctx).take(n)
+}
+
+// CommentsIter 返回一个逐个获取评论的 Iterator，评论接口需要回答的数字 ID，所以会先载入回答页面
+func (a *Answer) CommentsIter(ctx context.Context) *CommentIterator {
+	return a.client.commentsIter(ctx, "answer.comments", a.Page, "answers", a.GetID)
+}
+
 // GetCollectedNum 返回被收藏次数
 func (a *Answer) GetCollectedNum() int {
 	if value, ok := a.getIntField("collected-num"); ok {
@@ -188,6 +235,26 @@ func (a *Answer) GetCollectedNum() int {
 	return value
 }
 
+// GetCreatedTime 返回回答的发布时间。页面上较早的回答只显示日期，这时只精确到天；无法获取时返回零值
+func (a *Answer) GetCreatedTime() time.Time {
+	if value, ok := a.getTimeField("created-time"); ok {
+		return value
+	}
+	a.setTimes(a.Doc().Find("div.zm-item-answer.zm-item-expanded"), time.Now())
+	value, _ := a.getTimeField("created-time")
+	return value
+}
+
+// GetUpdatedTime 返回回答最后一次编辑的时间，没有编辑过的回答同 GetCreatedTime
+func (a *Answer) GetUpdatedTime() time.Time {
+	if value, ok := a.getTimeField("updated-time"); ok {
+		return value
+	}
+	a.setTimes(a.Doc().Find("div.zm-item-answer.zm-item-expanded"), time.Now())
+	value, _ := a.getTimeField("updated-time")
+	return value
+}
+
 func (a *Answer) String() string {
 	return fmt.Sprintf("<Answer: %s - %s>", a.GetAuthor().String(), a.Link)
 }
@@ -200,6 +267,44 @@ func (a *Answer) setUpvote(value int) {
 	a.setField("upvote", value)
 }
 
+// setTimes 从回答的 HTML 片段里解析发布时间和最后编辑时间，sel 是 div.zm-item-answer 或者包含它的元素。
+// 发布时间优先用 data-created 属性（Unix 时间戳），其次是 a.answer-date-link 的文字“发布于 ...”，
+// 编辑过的回答文字是“编辑于 ...”，发布时间在 data-tip 里，如 s$t$发布于 2016-03-09
+func (a *Answer) setTimes(sel *goquery.Selection, now time.Time) {
+	var created, updated time.Time
+	answerTag := sel.Filter("div.zm-item-answer").AddSelection(sel.Find("div.zm-item-answer")).First()
+	if value, ok := answerTag.Attr("data-created"); ok {
+		if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
+			created = time.Unix(ts, 0).In(zhihuLocation)
+		}
+	}
+
+	dateLink := sel.Find("a.answer-date-link").First()
+	text := strip(dateLink.Text())
+	switch {
+	case strings.HasPrefix(text, "编辑于"):
+		updated, _ = parseZhihuTime(strings.TrimPrefix(text, "编辑于"), now)
+		if tip, _ := dateLink.Attr("data-tip"); created.IsZero() && strings.Contains(tip, "发布于") {
+			created, _ = parseZhihuTime(tip[strings.Index(tip, "发布于")+len("发布于"):], now)
+		}
+	case strings.HasPrefix(text, "发布于"):
+		if created.IsZero() {
+			created, _ = parseZhihuTime(strings.TrimPrefix(text, "发布于"), now)
+		}
+		updated = created
+	default:
+		// 没有时间链接，只有 data-created
+		updated = created
+	}
+
+	if !created.IsZero() {
+		a.setField("created-time", created)
+	}
+	if !updated.IsZero() {
+		a.setField("updated-time", updated)
+	}
+}
+
 func upvoteTextToNum(text string) int {
 	rv := 0
 	if strings.HasSuffix(text, "K") {
@@ -232,7 +337,7 @@ func answerSelectionToHtml(sel *goquery.Selection) (string, error) {
 
 	sel.Find("a.zu-edit-button").Remove() // 把 “修改” 链接去掉
 
-	// 修复 img 的 src
+	// 修复 img 的 src，公式图片（/equation?tex=...）没有懒加载的属性，保留原来的 src
 	sel.Find("img").Each(func(_ int, tag *goquery.Selection) {
 		var src string
 		if tag.HasClass("origin_image") {
@@ -240,7 +345,9 @@ func answerSelectionToHtml(sel *goquery.Selection) (string, error) {
 		} else {
 			src, _ = tag.Attr("data-actualsrc")
 		}
-		tag.SetAttr("src", src)
+		if src != "" {
+			tag.SetAttr("src", src)
+		}
 		if tag.Next().Size() == 0 {
 			tag.AfterHtml("<br>")
 		}
@@ -249,14 +356,7 @@ func answerSelectionToHtml(sel *goquery.Selection) (string, error) {
 	// 修复 a 标签的 href，因为知乎的外链都是这种形式：https://link.zhihu.com/?target=xxx
 	sel.Find("a").Each(func(_ int, tag *goquery.Selection) {
 		href, _ := tag.Attr("href")
-		if strings.Contains(href, "target=") {
-			link, err := url.Parse(href)
-			if err != nil {
-				return
-			}
-			target := link.Query().Get("target")
-			tag.SetAttr("href", target)
-		}
+		tag.SetAttr("href", unwrapZhihuLink(href))
 	})
 
 	wrapper := `<html><head><meta charset="utf-8"></head><body></body></html>`
@@ -266,7 +366,7 @@ func answerSelectionToHtml(sel *goquery.Selection) (string, error) {
 	return doc.Html()
 }
 
-func newUserFromAnswerAuthorTag(sel *goquery.Selection) *User {
+func (c *Client) newUserFromAnswerAuthorTag(sel *goquery.Selection) *User {
 	if strip(sel.Text()) == "匿名用户" {
 		return ANONYMOUS
 	}
@@ -274,6 +374,6 @@ func newUserFromAnswerAuthorTag(sel *goquery.Selection) *User {
 	node := sel.Find("a.author-link")
 	userId := strip(node.Text())
 	urlPath, _ := node.Attr("href")
-	userLink := makeZhihuLink(urlPath)
-	return NewUser(userLink, userId)
+	userLink := c.makeZhihuLink(urlPath)
+	return newUser(c, userLink, userId)
 }
@@ -0,0 +1,134 @@
+package zhihu
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newReplayAnswer(t *testing.T) *Answer {
+	answer, err := newReplayClient().Answer("https://www.zhihu.com/question/41171543/answer/88475539", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := answer.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+	return answer
+}
+
+func Test_Answer(t *testing.T) {
+	answer := newReplayAnswer(t)
+
+	ioMap := map[string][2]int{
+		"GetID":           {answer.GetID(), 29734410},
+		"GetUpvote":       {answer.GetUpvote(), 8000},
+		"GetCommentsNum":  {answer.GetCommentsNum(), 316},
+		"GetCollectedNum": {answer.GetCollectedNum(), 1792},
+	}
+	for method, value := range ioMap {
+		if value[0] != value[1] {
+			t.Errorf("%s() returns %d, want %d", method, value[0], value[1])
+		}
+	}
+
+	question := answer.GetQuestion()
+	if question.Link != "https://www.zhihu.com/question/41171543" || question.GetTitle() != "如何评价第一局比赛 AlphaGo 战胜李世石？" {
+		t.Errorf("GetQuestion() returns error result: %s", question)
+	}
+
+	author := answer.GetAuthor()
+	if author.GetUserID() != "柯森杰" || author.Link != "https://www.zhihu.com/people/kesenjie" {
+		t.Errorf("GetAuthor() returns error result: %s", author)
+	}
+}
+
+func Test_AnswerGetContent(t *testing.T) {
+	content := newReplayAnswer(t).GetContent()
+
+	for _, expected := range []string{`src="https://pic2.zhimg.com/5d1ab2c1e0d1f0f2_b.jpg"`, `href="https://deepmind.com/alpha-go"`} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GetContent() should contain %s, got: %s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"<noscript>", "icon-external"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("GetContent() should not contain %s, got: %s", unexpected, content)
+		}
+	}
+}
+
+func Test_AnswerGetVoters(t *testing.T) {
+	voters := newReplayAnswer(t).GetVoters()
+	if len(voters) != 3 {
+		t.Fatalf("GetVoters() returns %d voters, want 3", len(voters))
+	}
+	if voters[0].GetUserID() != "张佳玮" || voters[0].Link != "https://www.zhihu.com/people/zhang-jia-wei" {
+		t.Errorf("GetVoters() returns error result: %s", voters[0])
+	}
+	if !voters[1].IsAnonymous() {
+		t.Errorf("GetVoters() returns error result: %s", voters[1])
+	}
+}
+
+func Test_AnswerGetComments(t *testing.T) {
+	comments := newReplayAnswer(t).GetComments()
+	if len(comments) != 32 {
+		t.Fatalf("GetComments() returns %d comments, want 32", len(comments))
+	}
+
+	first := comments[0]
+	if first.ID != 125290000 || first.Author.GetUserID() != "张佳玮" || first.Author.Link != "https://www.zhihu.com/people/zhang-jia-wei" ||
+		first.Content != "<p>第 1 条评论</p>" || first.Likes != 30 || first.ReplyTo != nil {
+		t.Errorf("comments[0] = %+v", first)
+	}
+	if expected := time.Date(2016, 3, 10, 6, 0, 0, 0, time.UTC); !first.CreatedAt.Equal(expected) {
+		t.Errorf("comments[0].CreatedAt = %s, want %s", first.CreatedAt, expected)
+	}
+
+	if reply := comments[1]; !reply.Author.IsAnonymous() || reply.ReplyTo == nil || reply.ReplyTo.GetUserID() != "张佳玮" {
+		t.Errorf("comments[1] should be an anonymous reply to 张佳玮: %+v", reply)
+	}
+	if deleted := comments[30]; !deleted.Author.IsAnonymous() || deleted.Author.Link != "" || deleted.Author.GetUserID() != "知乎用户" {
+		t.Errorf("comments[30] should be written by a deleted user: %+v", deleted)
+	}
+	if deleted := comments[31]; !deleted.Deleted || deleted.Content != "" || deleted.ReplyTo.GetUserID() != "周源" {
+		t.Errorf("comments[31] should be deleted: %+v", deleted)
+	}
+
+	if got := newReplayAnswer(t).GetCommentsN(3); len(got) != 3 || got[2].ID != 125290002 {
+		t.Errorf("GetCommentsN(3) returns %v", got)
+	}
+}
+
+func Test_AnswerTimes(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, zhihuLocation)
+	}
+	check := func(name string, a *Answer, created, updated time.Time) {
+		t.Helper()
+		if !created.IsZero() && !a.GetCreatedTime().Equal(created) {
+			t.Errorf("%s: GetCreatedTime() returns %s, want %s", name, a.GetCreatedTime(), created)
+		}
+		if !a.GetUpdatedTime().Equal(updated) {
+			t.Errorf("%s: GetUpdatedTime() returns %s, want %s", name, a.GetUpdatedTime(), updated)
+		}
+	}
+
+	// 回答页面：编辑过的回答，发布时间在 data-tip 里
+	check("answer page", newReplayAnswer(t), date(2016, 3, 9), date(2016, 3, 10))
+
+	// 问题页面和 Ajax 接口，第二个回答有 data-created
+	answers := newReplayQuestion(t).GetAllAnswers()
+	check("question page", answers[0], date(2016, 3, 9), date(2016, 3, 10))
+	check("data-created", answers[1], time.Unix(1457512345, 0), time.Unix(1457512345, 0))
+	check("ajax", answers[2], date(2016, 3, 10), date(2016, 3, 10))
+
+	answers = newReplayCollection(t).GetAnswers()
+	check("collection", answers[0], date(2013, 6, 21), date(2013, 6, 21))
+	check("collection edited", answers[1], time.Time{}, date(2014, 2, 13))
+
+	answers = newReplayUser(t).GetAnswersN(2)
+	check("user answers", answers[0], date(2013, 5, 8), date(2014, 2, 13))
+	check("user answers data-created", answers[1], time.Unix(1370000000, 0), time.Unix(1370000000, 0))
+}
@@ -0,0 +1,237 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Article 是一篇专栏文章，如 https://zhuanlan.zhihu.com/p/20761239，数据来自专栏的 JSON 接口
+type Article struct {
+	// Link 是该文章的链接
+	Link string
+
+	// client 是该文章绑定的 Client
+	client *Client
+
+	// id 是文章的数字 ID
+	id int
+
+	// title 是文章的标题
+	title string
+
+	data apiResource[articleData]
+}
+
+// articleData 是 /api/posts/{id} 返回的 JSON，专栏的文章列表里也是这个格式
+type articleData struct {
+	Slug          int      `json:"slug"`
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	PublishedTime string   `json:"publishedTime"`
+	LikesCount    int      `json:"likesCount"`
+	CommentsCount int      `json:"commentsCount"`
+	URL           string   `json:"url"`
+	Author        *apiUser `json:"author"`
+	Column        *struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"column"`
+	Topics []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"topics"`
+}
+
+// Article 创建一篇绑定在该 Client 上的专栏文章，link 是文章的链接，如 https://zhuanlan.zhihu.com/p/20761239，
+// title 是文章的标题，可以为空，需要时再从接口获取。链接不合法时返回 ErrInvalidURL
+func (c *Client) Article(link string, title string) (*Article, error) {
+	m := c.matchColumnURL(link, reArticleID)
+	if m == nil {
+		return nil, wrapError(ErrInvalidURL, "专栏文章链接不正确：%s", link)
+	}
+	id, _ := strconv.Atoi(m[1])
+	return newArticle(c, link, id, title), nil
+}
+
+func newArticle(client *Client, link string, id int, title string) *Article {
+	return &Article{
+		Link:   link,
+		client: client,
+		id:     id,
+		title:  title,
+	}
+}
+
+// newArticleFromData 用列表接口返回的数据创建文章，之后的 GetXXX 不需要再请求
+func (c *Client) newArticleFromData(data *articleData) *Article {
+	article := newArticle(c, c.makeColumnLink(fmt.Sprintf("/p/%d", data.Slug)), data.Slug, data.Title)
+	article.data.set(data)
+	return article
+}
+
+// GetID 返回文章的数字 ID
+func (a *Article) GetID() int {
+	return a.id
+}
+
+// GetTitle 返回文章的标题
+func (a *Article) GetTitle() string {
+	if a.title != "" {
+		return a.title
+	}
+	return a.get().Title
+}
+
+// GetAuthor 返回文章的作者
+func (a *Article) GetAuthor() *User {
+	return a.client.newUserFromAPI(a.get().Author)
+}
+
+// GetColumn 返回文章所属的专栏，不属于任何专栏时返回 nil
+func (a *Article) GetColumn() *Column {
+	column := a.get().Column
+	if column == nil || column.Slug == "" {
+		return nil
+	}
+	return newColumn(a.client, a.client.makeColumnLink("/"+column.Slug), column.Slug, column.Name)
+}
+
+// GetContent 返回文章的内容，HTML 格式
+func (a *Article) GetContent() string {
+	return a.get().Content
+}
+
+// GetPublishedTime 返回文章的发布时间
+func (a *Article) GetPublishedTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, a.get().PublishedTime)
+	return t
+}
+
+// GetLikesNum 返回文章的点赞数
+func (a *Article) GetLikesNum() int {
+	return a.get().LikesCount
+}
+
+// GetCommentsNum 返回文章的评论数量
+func (a *Article) GetCommentsNum() int {
+	return a.get().CommentsCount
+}
+
+// GetTopics 返回文章的话题
+func (a *Article) GetTopics() []*Topic {
+	var topics []*Topic
+	for _, topic := range a.get().Topics {
+		link := a.client.makeZhihuLink(fmt.Sprintf("/topic/%d", topic.ID))
+		topics = append(topics, newTopic(a.client, link, topic.Name))
+	}
+	return topics
+}
+
+// GetCommentsN 返回前 n 条评论，如果 n < 0，返回所有评论
+func (a *Article) GetCommentsN(n int) []*Comment {
+	comments, _ := a.GetCommentsNCtx(context.Background(), n)
+	return comments
+}
+
+// GetCommentsNCtx 同 GetCommentsN，ctx 取消或超时后停止翻页并返回错误
+func (a *Article) GetCommentsNCtx(ctx context.Context, n int) ([]*Comment, error) {
+	return a.CommentsIter(ctx).take(n)
+}
+
+// GetComments 返回文章的所有评论
+func (a *Article) GetComments() []*Comment {
+	return a.GetCommentsN(-1)
+}
+
+// CommentsIter 返回一个逐个获取评论的 Iterator
+func (a *Article) CommentsIter(ctx context.Context) *CommentIterator {
+	return newIterator(ctx, "article.comments", a.Link, func(ctx context.Context, cursor *Cursor) ([]*Comment, bool, error) {
+		link := a.client.makeColumnLink(fmt.Sprintf("/api/posts/%d/comments?limit=%d&offset=%d", a.id, pageSize, cursor.Offset))
+		var result []commentData
+		if err := a.client.getJSON(ctx, link, &result); err != nil {
+			a.client.logger.Error("获取评论失败", "url", link, "err", err)
+			return nil, false, err
+		}
+
+		comments := make([]*Comment, 0, len(result))
+		for _, data := range result {
+			comments = append(comments, a.client.newComment(data))
+		}
+		return comments, len(result) == pageSize, nil
+	})
+}
+
+// ToMarkdown 把文章导出到 markdown 文件，格式同 Answer.ToMarkdown，upvote 是点赞数。载入失败时返回错误，不会写入文件
+func (a *Article) ToMarkdown(filename string) error {
+	if !strings.HasSuffix(filename, ".md") && !strings.HasSuffix(filename, ".markdown") {
+		filename += ".md"
+	}
+	if _, err := a.data.load(context.Background(), a.client, a.apiLink(), false); err != nil {
+		return err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(a.GetContent()))
+	if err != nil {
+		return err
+	}
+
+	md := markdownFrontMatter(a.GetTitle(), a.GetAuthor().GetUserID(), a.GetLikesNum(), a.Link)
+	md += htmlToMarkdown(doc.Find("body"))
+	return saveString(filename, md)
+}
+
+// ToHtml 把文章导出到 HTML 文件。专栏页面是用 JavaScript 渲染的，所以导出的是标题、作者和正文组成的页面。
+// 载入失败时返回错误，不会写入文件
+func (a *Article) ToHtml(filename string) error {
+	if !strings.HasSuffix(filename, ".html") {
+		filename += ".html"
+	}
+	if _, err := a.data.load(context.Background(), a.client, a.apiLink(), false); err != nil {
+		return err
+	}
+
+	title := html.EscapeString(a.GetTitle())
+	page := fmt.Sprintf(`<html><head><meta charset="utf-8"><title>%s</title></head>`+
+		`<body><h1>%s</h1><p><a href="%s">%s</a></p>%s</body></html>`,
+		title, title, html.EscapeString(a.GetAuthor().Link), html.EscapeString(a.GetAuthor().GetUserID()), a.GetContent())
+	return saveString(filename, page)
+}
+
+// Refresh 会重新载入文章的数据
+func (a *Article) Refresh() error {
+	return a.RefreshCtx(context.Background())
+}
+
+// RefreshCtx 同 Refresh，ctx 取消或超时后请求会被中止
+func (a *Article) RefreshCtx(ctx context.Context) error {
+	_, err := a.data.load(ctx, a.client, a.apiLink(), true)
+	return err
+}
+
+// Err 返回最近一次载入数据时的错误，没有出错则返回 nil
+func (a *Article) Err() error {
+	return a.data.Err()
+}
+
+func (a *Article) String() string {
+	return fmt.Sprintf("<Article: %s - %s>", a.GetTitle(), a.Link)
+}
+
+func (a *Article) apiLink() string {
+	return a.client.makeColumnLink(fmt.Sprintf("/api/posts/%d", a.id))
+}
+
+// get 返回文章的数据，载入失败时返回零值，错误可以通过 Err 获取
+func (a *Article) get() *articleData {
+	data, err := a.data.load(context.Background(), a.client, a.apiLink(), false)
+	if err != nil {
+		return &articleData{}
+	}
+	return data
+}
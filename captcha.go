@@ -0,0 +1,188 @@
+package zhihu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // 知乎的验证码是 gif 格式
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// CaptchaSolver 用于识别登录时的验证码，传入验证码图片的内容，返回识别出来的验证码
+type CaptchaSolver interface {
+	Solve(image []byte) (string, error)
+}
+
+// CaptchaSolverFunc 把一个普通函数转换成 CaptchaSolver，可以用来接入第三方的打码服务
+type CaptchaSolverFunc func(image []byte) (string, error)
+
+// Solve 调用 f(image)
+func (f CaptchaSolverFunc) Solve(image []byte) (string, error) {
+	return f(image)
+}
+
+// InteractiveCaptchaSolver 把验证码保存到文件，调用外部程序打开，再从标准输入读取用户输入的验证码。
+// 这是 Session 默认使用的方式，需要图形界面
+type InteractiveCaptchaSolver struct {
+	// Dir 是保存验证码文件的目录，为空时使用当前目录
+	Dir string
+
+	// Logger 用于输出调用外部程序的日志，为 nil 时使用所属 Session 的 Logger（单独使用时是全局的 Logger）
+	Logger Logger
+}
+
+// Solve 保存并打开验证码文件，然后读取用户的输入
+func (solver *InteractiveCaptchaSolver) Solve(img []byte) (string, error) {
+	dir := solver.Dir
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("获取 CWD 失败：%s", err.Error())
+		}
+		dir = cwd
+	}
+
+	fileExt := "gif"
+	if parts := strings.Split(http.DetectContentType(img), "/"); len(parts) == 2 && parts[0] == "image" {
+		fileExt = parts[1]
+	}
+	verifyImg := filepath.Join(dir, "verify."+fileExt)
+	if err := ioutil.WriteFile(verifyImg, img, 0666); err != nil {
+		return "", fmt.Errorf("保存验证码文件失败：%s", err.Error())
+	}
+
+	log := solver.Logger
+	if log == nil {
+		log = logger
+	}
+	if err := openCaptchaFile(verifyImg, log); err != nil {
+		log.Warn("打开验证码文件失败，请自行打开", "file", verifyImg, "err", err)
+	}
+	fmt.Print(color.CyanString("请输入验证码："))
+	return readCaptchaInput(os.Stdin)
+}
+
+// TerminalCaptchaSolver 用 ANSI 色块把验证码直接打印到终端，再读取用户输入的验证码，
+// 适用于没有图形界面（如通过 SSH 登录的服务器）的情况。终端需要支持 24 位色
+type TerminalCaptchaSolver struct {
+	// Input 是读取验证码的来源，为 nil 时使用 os.Stdin
+	Input io.Reader
+
+	// Output 是打印验证码的目标，为 nil 时使用 os.Stdout
+	Output io.Writer
+
+	// Width 是打印的最大宽度（字符数），图片更宽时会等比缩小；为 0 时不限制
+	Width int
+}
+
+// Solve 打印验证码并读取用户的输入
+func (solver *TerminalCaptchaSolver) Solve(img []byte) (string, error) {
+	input, output := solver.Input, solver.Output
+	if input == nil {
+		input = os.Stdin
+	}
+	if output == nil {
+		output = os.Stdout
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(img))
+	if err != nil {
+		return "", fmt.Errorf("解码验证码图片失败：%s", err.Error())
+	}
+	io.WriteString(output, renderANSIBlocks(decoded, solver.Width))
+
+	fmt.Fprint(output, color.CyanString("请输入验证码："))
+	return readCaptchaInput(input)
+}
+
+// renderANSIBlocks 把图片渲染成 ANSI 色块，每个字符是一个 “▀”，
+// 前景色是上面的像素，背景色是下面的像素，所以一行字符可以表示两行像素
+func renderANSIBlocks(img image.Image, maxWidth int) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(width) / float64(maxWidth)
+		width = maxWidth
+		height = int(float64(height) / scale)
+	}
+
+	rgb := func(x, y int) (uint32, uint32, uint32) {
+		px := bounds.Min.X + int(float64(x)*scale)
+		py := bounds.Min.Y + int(float64(y)*scale)
+		r, g, b, _ := img.At(px, py).RGBA()
+		return r >> 8, g >> 8, b >> 8
+	}
+
+	var buf bytes.Buffer
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			r, g, b := rgb(x, y)
+			fmt.Fprintf(&buf, "\x1b[38;2;%d;%d;%dm", r, g, b)
+			if y+1 < height {
+				r, g, b = rgb(x, y+1)
+				fmt.Fprintf(&buf, "\x1b[48;2;%d;%d;%dm", r, g, b)
+			}
+			buf.WriteString("▀")
+		}
+		buf.WriteString("\x1b[0m\n")
+	}
+	return buf.String()
+}
+
+func openCaptchaFile(filename string, log Logger) error {
+	var args []string
+	switch runtime.GOOS {
+	case "linux":
+		args = []string{"xdg-open", filename}
+	case "darwin":
+		args = []string{"open", filename}
+	case "freebsd":
+		args = []string{"open", filename}
+	case "netbsd":
+		args = []string{"open", filename}
+	case "windows":
+		var (
+			cmd      = "url.dll,FileProtocolHandler"
+			runDll32 = filepath.Join(os.Getenv("SYSTEMROOT"), "System32", "rundll32.exe")
+		)
+		args = []string{runDll32, cmd, filename}
+	default:
+		fmt.Printf("无法确定操作系统，请自行打开验证码 %s 文件，并输入验证码。", filename)
+		return nil
+	}
+
+	log.Debug("调用外部程序渲染验证码", "command", strings.Join(args, " "))
+
+	err := exec.Command(args[0], args[1:]...).Run()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// readCaptchaInput 读取一行用户输入的验证码，没有读到（如标准输入已经关闭）或者输入为空时返回错误
+func readCaptchaInput(input io.Reader) (string, error) {
+	line, err := bufio.NewReader(input).ReadString('\n')
+	captcha := strip(line)
+	if err != nil && (err != io.EOF || captcha == "") {
+		return "", fmt.Errorf("读取验证码失败：%s", err.Error())
+	}
+	if captcha == "" {
+		return "", fmt.Errorf("没有输入验证码")
+	}
+	return captcha, nil
+}
@@ -0,0 +1,88 @@
+package zhihu
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func newTestCaptcha(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_TerminalCaptchaSolver(t *testing.T) {
+	var output bytes.Buffer
+	solver := &TerminalCaptchaSolver{
+		Input:  strings.NewReader("abcd\n"),
+		Output: &output,
+	}
+
+	captcha, err := solver.Solve(newTestCaptcha(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captcha != "abcd" {
+		t.Errorf("Solve returns %q, want %q", captcha, "abcd")
+	}
+
+	// 4x4 的图片渲染成两行，每行 4 个色块
+	rendered := output.String()
+	if got := strings.Count(rendered, "▀"); got != 8 {
+		t.Errorf("rendered %d blocks, want 8", got)
+	}
+	if !strings.Contains(rendered, "\x1b[38;2;255;0;0m") {
+		t.Error("rendered blocks do not use the image color")
+	}
+}
+
+func Test_CaptchaSolverFunc(t *testing.T) {
+	var got []byte
+	var solver CaptchaSolver = CaptchaSolverFunc(func(image []byte) (string, error) {
+		got = image
+		return "1234", nil
+	})
+
+	img := newTestCaptcha(t)
+	captcha, err := solver.Solve(img)
+	if err != nil || captcha != "1234" || !bytes.Equal(got, img) {
+		t.Errorf("CaptchaSolverFunc returns %q, %v", captcha, err)
+	}
+}
+
+func Test_readCaptchaInput(t *testing.T) {
+	ioMap := map[string]string{
+		"abcd\n":   "abcd",
+		" abcd \n": "abcd",
+		"abcd":     "abcd", // 最后一行没有换行符
+	}
+	for input, expected := range ioMap {
+		if got, err := readCaptchaInput(strings.NewReader(input)); err != nil || got != expected {
+			t.Errorf("readCaptchaInput(%q) returns %q, %v, want %q", input, got, err, expected)
+		}
+	}
+
+	// 标准输入已经关闭，或者直接回车
+	for _, input := range []string{"", "\n"} {
+		if got, err := readCaptchaInput(strings.NewReader(input)); err == nil {
+			t.Errorf("readCaptchaInput(%q) returns %q without error", input, got)
+		}
+	}
+
+	solver := &TerminalCaptchaSolver{Input: strings.NewReader(""), Output: new(bytes.Buffer)}
+	if _, err := solver.Solve(newTestCaptcha(t)); err == nil {
+		t.Error("Solve returns nil error on EOF")
+	}
+}
@@ -0,0 +1,173 @@
+package zhihu
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Client 是访问知乎的客户端，持有自己的 Session 和 Logger。
+// 通过 Client 创建的 User, Question, Answer, Collection, Topic 都绑定在该 Client 上，
+// 它们发起的所有请求都经由这个 Client 的 Session，因此一个进程里可以同时使用多个账号
+type Client struct {
+	session *Session
+	logger  Logger
+}
+
+// NewClient 创建一个 Client，如果 session 为 nil，则新建一个 Session
+func NewClient(session *Session) *Client {
+	if session == nil {
+		session = NewSession()
+	}
+	return &Client{
+		session: session,
+		logger:  session.logger,
+	}
+}
+
+// Session 返回该 Client 使用的 Session
+func (c *Client) Session() *Session {
+	return c.session
+}
+
+// SetSession 替换该 Client 使用的 Session，已经创建的对象也会使用新的 Session
+func (c *Client) SetSession(s *Session) {
+	c.session = s
+	s.logger = c.logger
+}
+
+// SetLogger 设置该 Client 及其 Session 使用的 Logger，l 为 nil 时不输出任何日志
+func (c *Client) SetLogger(l Logger) {
+	if l == nil {
+		l = DiscardLogger
+	}
+	c.logger = l
+	c.session.logger = l
+}
+
+// Init 从配置文件读取账号信息并登录
+func (c *Client) Init(cfgFile string) error {
+	if err := c.session.LoadConfig(cfgFile); err != nil {
+		return err
+	}
+	return c.session.Login()
+}
+
+// User 创建一个绑定在该 Client 上的用户对象，参数的含义同 NewUser，参数不合法时返回 ErrInvalidURL
+func (c *Client) User(link string, userID string) (*User, error) {
+	if link == "" && !isAnonymous(userID) {
+		return nil, wrapError(ErrInvalidURL, "用户链接为空，且不是匿名用户：%s", userID)
+	}
+	return newUser(c, link, userID), nil
+}
+
+// Question 创建一个绑定在该 Client 上的问题对象，参数的含义同 NewQuestion，链接不合法时返回 ErrInvalidURL
+func (c *Client) Question(link string, title string) (*Question, error) {
+	if !c.session.validQuestionURL(link) {
+		return nil, wrapError(ErrInvalidURL, "问题链接不正确：%s", link)
+	}
+	return newQuestion(c, link, title), nil
+}
+
+// Answer 创建一个绑定在该 Client 上的回答对象，参数的含义同 NewAnswer，链接为空时返回 ErrInvalidURL
+func (c *Client) Answer(link string, question *Question, author *User) (*Answer, error) {
+	if link == "" {
+		return nil, wrapError(ErrInvalidURL, "回答链接为空")
+	}
+	return newAnswer(c, link, question, author), nil
+}
+
+// Collection 创建一个绑定在该 Client 上的收藏夹对象，参数的含义同 NewCollection，链接不合法时返回 ErrInvalidURL
+func (c *Client) Collection(link string, name string, creator *User) (*Collection, error) {
+	if !c.session.validCollectionURL(link) {
+		return nil, wrapError(ErrInvalidURL, "收藏夹链接不正确：%s", link)
+	}
+	return newCollection(c, link, name, creator), nil
+}
+
+// Topic 创建一个绑定在该 Client 上的话题对象，参数的含义同 NewTopic，链接不合法时返回 ErrInvalidURL
+func (c *Client) Topic(link string, name string) (*Topic, error) {
+	if !c.session.validTopicURL(link) {
+		return nil, wrapError(ErrInvalidURL, "话题链接不正确：%s", link)
+	}
+	return newTopic(c, link, name), nil
+}
+
+// newDocumentFromURL 会请求给定的 url，并返回一个 goquery.Document 对象用于解析
+func (c *Client) newDocumentFromURL(ctx context.Context, url string) (*goquery.Document, error) {
+	resp, err := c.session.GetCtx(ctx, url)
+	if err != nil {
+		c.logger.Error("请求失败", "url", url, "err", err)
+		return nil, err
+	}
+	if err = checkResponse(resp); err != nil {
+		c.logger.Error("请求失败", "url", url, "err", err)
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromResponse(resp)
+	if err != nil {
+		c.logger.Error("解析页面失败", "url", url, "err", err)
+		return nil, wrapError(ErrParse, "%s", err.Error())
+	}
+
+	return doc, nil
+}
+
+// ajaxJSON 发起一个 Ajax 请求，并把返回的 JSON 解析到 result 中。
+// 这些请求只是获取数据，可以放心地重试
+func (c *Client) ajaxJSON(ctx context.Context, link string, form url.Values, referer string, result interface{}) error {
+	body := strings.NewReader(form.Encode())
+	resp, err := c.session.AjaxCtx(WithRetryNonIdempotent(ctx), link, body, referer)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp); err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return wrapError(ErrParse, "解析 %s 返回的 JSON 失败：%s", link, err.Error())
+	}
+	return nil
+}
+
+// getJSON 发起一个 GET 请求，并把返回的 JSON 解析到 result 中
+func (c *Client) getJSON(ctx context.Context, link string, result interface{}) error {
+	resp, err := c.session.GetCtx(ctx, link)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp); err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return wrapError(ErrParse, "解析 %s 返回的 JSON 失败：%s", link, err.Error())
+	}
+	return nil
+}
+
+var (
+	defaultClient = NewClient(nil) // 默认的 Client，NewUser, NewQuestion 等函数创建的对象都绑定在它上面
+)
+
+// DefaultClient 返回默认的 Client
+func DefaultClient() *Client {
+	return defaultClient
+}
+
+// Init 用于传入配置文件，配置默认 Client 的 Session 并登录
+func Init(cfgFile string) error {
+	return defaultClient.Init(cfgFile)
+}
+
+// SetSession 用于替换默认 Client 的 session
+func SetSession(s *Session) {
+	defaultClient.SetSession(s)
+}
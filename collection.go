@@ -1,11 +1,12 @@
 package zhihu
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -21,14 +22,20 @@ type Collection struct {
 	name string
 }
 
-// NewCollection 创建一个收藏夹对象，返回 *Collection
+// NewCollection 创建一个收藏夹对象，返回 *Collection，链接不合法时会 panic。
+//
+// Deprecated: 请使用 Client.Collection（如 DefaultClient().Collection），链接不合法时返回 ErrInvalidURL 而不是 panic
 func NewCollection(link string, name string, creator *User) *Collection {
-	if !validCollectionURL(link) {
+	collection, err := defaultClient.Collection(link, name, creator)
+	if err != nil {
 		panic("收藏夹链接不正确：" + link)
 	}
+	return collection
+}
 
+func newCollection(client *Client, link string, name string, creator *User) *Collection {
 	return &Collection{
-		Page:    newZhihuPage(link),
+		Page:    newZhihuPage(client, link),
 		creator: creator,
 		name:    name,
 	}
@@ -36,35 +43,25 @@ func NewCollection(link string, name string, creator *User) *Collection {
 
 // GetName 返回收藏夹的名字
 func (c *Collection) GetName() string {
-	if c.name != "" {
-		return c.name
-	}
-
-	doc := c.Doc()
-
-	// <h2 class="zm-item-title zm-editable-content" id="zh-fav-head-title">
-	//   恩恩恩 大力一点，不要停～
-	// </h2>
-	c.name = strip(doc.Find("h2#zh-fav-head-title").Text())
-	return c.name
+	return lazyField(c.Page, &c.name, func() string {
+		// <h2 class="zm-item-title zm-editable-content" id="zh-fav-head-title">
+		//   恩恩恩 大力一点，不要停～
+		// </h2>
+		return strip(c.Doc().Find("h2#zh-fav-head-title").Text())
+	})
 }
 
 // GetCreator 返回收藏夹的创建者
 func (c *Collection) GetCreator() *User {
-	if c.creator != nil {
-		return c.creator
-	}
-
-	doc := c.Doc()
-
-	// <h2 class="zm-list-content-title">
-	//   <a href="/people/leonyoung">李阳良</a>
-	// </h2>
-	sel := doc.Find("h2.zm-list-content-title a")
-	userId := strip(sel.Text())
-	linkPath, _ := sel.Attr("href")
-	c.creator = NewUser(makeZhihuLink(linkPath), userId)
-	return c.creator
+	return lazyField(c.Page, &c.creator, func() *User {
+		// <h2 class="zm-list-content-title">
+		//   <a href="/people/leonyoung">李阳良</a>
+		// </h2>
+		sel := c.Doc().Find("h2.zm-list-content-title a")
+		userId := strip(sel.Text())
+		linkPath, _ := sel.Attr("href")
+		return newUser(c.client, c.client.makeZhihuLink(linkPath), userId)
+	})
 }
 
 // GetFollowersNum 返回收藏夹的关注者数量
@@ -86,17 +83,20 @@ func (c *Collection) GetFollowersNum() int {
 
 // GetFollowersN 返回 n 个关注该收藏夹的用户，如果 n < 0，返回所有关注者
 func (c *Collection) GetFollowersN(n int) []*User {
-	var (
-		link = urlJoin(c.Link, "/followers")
-		xsrf = c.GetXSRF()
-	)
-	users, err := ajaxGetFollowers(link, xsrf, n)
-	if err != nil {
-		return nil
-	}
+	users, _ := c.GetFollowersNCtx(context.Background(), n)
 	return users
 }
 
+// GetFollowersNCtx 同 GetFollowersN，ctx 取消或超时后停止翻页并返回错误
+func (c *Collection) GetFollowersNCtx(ctx context.Context, n int) ([]*User, error) {
+	return c.FollowersIter(ctx).take(n)
+}
+
+// FollowersIter 返回一个逐个获取关注者的 Iterator
+func (c *Collection) FollowersIter(ctx context.Context) *UserIterator {
+	return c.client.followersIter(ctx, "collection.followers", c.Page, urlJoin(c.Link, "/followers"))
+}
+
 // GetFollowers 返回关注该收藏夹的用户
 func (c *Collection) GetFollowers() []*User {
 	return c.GetFollowersN(c.GetFollowersNum())
@@ -104,40 +104,19 @@ func (c *Collection) GetFollowers() []*User {
 
 // GetQuestionsN 返回前 n 个问题，如果 n < 0，返回所有问题
 func (c *Collection) GetQuestionsN(n int) []*Question {
-	if n == 0 {
-		return nil
-	}
-
-	// 先获取第一页的问题
-	questions := getQuestionsFromDoc(c.Doc())
-
-	totalPages := c.totalPages()
-	if totalPages == 1 {
-		if n < 0 || n > len(questions) {
-			return questions
-		}
-		return questions[0:n]
-	}
-
-	// 再分页查询其他问题
-	currentPage := 2
-	for currentPage <= totalPages {
-		link := fmt.Sprintf("%s?page=%d", c.Link, currentPage)
-		doc, err := newDocumentFromURL(link)
-		if err != nil {
-			logger.Error("解析页面失败：%s, %s", link, err.Error())
-			return nil
-		}
+	questions, _ := c.GetQuestionsNCtx(context.Background(), n)
+	return questions
+}
 
-		newQuestions := getQuestionsFromDoc(doc)
-		questions = append(questions, newQuestions...)
-		if n > 0 && len(questions) >= n {
-			return questions[0:n]
-		}
-		currentPage++
-	}
+// GetQuestionsNCtx 同 GetQuestionsN，ctx 取消或超时后停止翻页并返回错误
+func (c *Collection) GetQuestionsNCtx(ctx context.Context, n int) ([]*Question, error) {
+	return c.QuestionsIter(ctx).take(n)
+}
 
-	return questions
+// QuestionsIter 返回一个逐个获取收藏夹里的问题的 Iterator
+func (c *Collection) QuestionsIter(ctx context.Context) *QuestionIterator {
+	return newIterator(ctx, "collection.questions", c.Link, collectionPageFetcher(c, c.client.getQuestionsFromDoc)).
+		concurrent(c.client.session.concurrency)
 }
 
 // GetQuestions 返回收藏夹里所有的问题
@@ -147,39 +126,44 @@ func (c *Collection) GetQuestions() []*Question {
 
 // GetAnswersN 返回 n 个回答，如果 n < 0，返回所有回答
 func (c *Collection) GetAnswersN(n int) []*Answer {
-	if n == 0 {
-		return nil
-	}
+	answers, _ := c.GetAnswersNCtx(context.Background(), n)
+	return answers
+}
 
-	// 先获取第一页的回答
-	answers := getAnswersFromDoc(c.Doc())
+// GetAnswersNCtx 同 GetAnswersN，ctx 取消或超时后停止翻页并返回错误
+func (c *Collection) GetAnswersNCtx(ctx context.Context, n int) ([]*Answer, error) {
+	return c.AnswersIter(ctx).take(n)
+}
 
-	totalPages := c.totalPages()
-	if totalPages == 1 {
-		if n < 0 || n > len(answers) {
-			return answers
-		}
-		return answers[0:n]
-	}
+// AnswersIter 返回一个逐个获取收藏夹里的回答的 Iterator
+func (c *Collection) AnswersIter(ctx context.Context) *AnswerIterator {
+	return newIterator(ctx, "collection.answers", c.Link, collectionPageFetcher(c, c.client.getAnswersFromDoc)).
+		concurrent(c.client.session.concurrency)
+}
 
-	// 在分页查询
-	currentPage := 2
-	for currentPage <= totalPages {
-		link := fmt.Sprintf("%s?page=%d", c.Link, currentPage)
-		doc, err := newDocumentFromURL(link)
+// collectionPageFetcher 返回按页获取收藏夹内容的 pageFetcher，第一页就是收藏夹页面，之后是 ?page=2, ?page=3...
+func collectionPageFetcher[T any](c *Collection, parse func(*goquery.Document) []T) pageFetcher[T] {
+	return func(ctx context.Context, cursor *Cursor) ([]T, bool, error) {
+		doc, err := c.DocCtx(ctx)
 		if err != nil {
-			logger.Error("解析页面失败：%s, %s", link, err.Error())
-			return nil
+			return nil, false, err
 		}
 
-		newAnswers := getAnswersFromDoc(doc)
-		answers = append(answers, newAnswers...)
-		if n > 0 && len(answers) >= n {
-			return answers[0:n]
+		totalPages := c.totalPages()
+		page := cursor.Page
+		if page >= totalPages {
+			return nil, false, nil
+		}
+		if page > 0 {
+			link := fmt.Sprintf("%s?page=%d", c.Link, page+1)
+			doc, err = c.client.newDocumentFromURL(ctx, link)
+			if err != nil {
+				c.client.logger.Error("解析页面失败", "url", link, "err", err)
+				return nil, false, err
+			}
 		}
-		currentPage++
+		return parse(doc), page+1 < totalPages, nil
 	}
-	return answers
 }
 
 // GetAnswers 返回收藏夹里所有的回答
@@ -189,20 +173,29 @@ func (c *Collection) GetAnswers() []*Answer {
 
 // GetQuestionsNum 返回收藏夹的问题数量
 func (c *Collection) GetQuestionsNum() int {
+	num, _ := c.GetQuestionsNumCtx(context.Background())
+	return num
+}
+
+// GetQuestionsNumCtx 同 GetQuestionsNum，ctx 取消或超时后请求会被中止，并返回错误
+func (c *Collection) GetQuestionsNumCtx(ctx context.Context) (int, error) {
 	if value, ok := c.getIntField("question-num"); ok {
-		return value
+		return value, nil
 	}
 
 	// 根据分页情况来计算问题数量
 	// 收藏夹页面，每一页固定 10 个问题，每个问题下可能有多个答案；
+	lastPage, err := c.DocCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
 	totalPages := c.totalPages()
-	lastPage := c.Doc()
 
 	if totalPages > 1 {
-		lp, err := newDocumentFromURL(fmt.Sprintf("%s?page=%d", c.Link, totalPages))
+		lp, err := c.client.newDocumentFromURL(ctx, fmt.Sprintf("%s?page=%d", c.Link, totalPages))
 		if err != nil {
-			logger.Error("获取收藏夹最后一页失败：%s", err.Error())
-			return 0
+			c.client.logger.Error("获取收藏夹最后一页失败", "collection", c.Link, "err", err)
+			return 0, err
 		}
 		lastPage = lp
 	}
@@ -210,10 +203,16 @@ func (c *Collection) GetQuestionsNum() int {
 	numOnLastPage := lastPage.Find("#zh-list-answer-wrap h2.zm-item-title").Size()
 	rv := (totalPages-1)*10 + numOnLastPage
 	c.setField("question-num", rv)
-	return rv
+	return rv, nil
 }
 
 // GetAnswersNum 返回收藏夹的答案数量
+func (c *Collection) GetAnswersNum() int {
+	num, _ := c.GetAnswersNumCtx(context.Background())
+	return num
+}
+
+// GetAnswersNumCtx 同 GetAnswersNum，ctx 取消或超时后请求会被中止，并返回错误。
 // 获取答案数量有这几种方式：
 // 	1. 在收藏夹页面（/collections/1234567），遍历每一页，累计每页的回答数量。总请求数等于分页数。
 //	2. 在收藏夹创建者的个人主页，收藏夹栏目（people/xxyy/collections），有每个收藏夹的简介，
@@ -222,9 +221,14 @@ func (c *Collection) GetQuestionsNum() int {
 // 最终的方案可以综合以上两种方式，以收藏夹页面分页数做依据：
 //  如果页数大于 3（经验值），则采用方法 2；否则用方法 1
 // 希望能通过这样的方式来减少请求数，获得更好的性能。
-func (c *Collection) GetAnswersNum() int {
+func (c *Collection) GetAnswersNumCtx(ctx context.Context) (int, error) {
 	if value, ok := c.getIntField("answer-num"); ok {
-		return value
+		return value, nil
+	}
+
+	firstPage, err := c.DocCtx(ctx)
+	if err != nil {
+		return 0, err
 	}
 
 	rv := 0
@@ -233,14 +237,17 @@ func (c *Collection) GetAnswersNum() int {
 		// 从个人主页上获取
 		page := 1
 		linkFmt := urlJoin(c.GetCreator().Link, "/collections?page=%d")
-		collectionHref := strings.Split(c.Link, "zhihu.com")[1]
+		collectionHref := c.Link
+		if u, err := url.Parse(c.Link); err == nil {
+			collectionHref = u.Path
+		}
 		selector := fmt.Sprintf(`a.zm-profile-fav-item-title[href="%s"]`, collectionHref)
 		for {
 			creatorCollectionLink := fmt.Sprintf(linkFmt, page)
-			doc, err := newDocumentFromURL(creatorCollectionLink)
+			doc, err := c.client.newDocumentFromURL(ctx, creatorCollectionLink)
 			if err != nil {
-				logger.Error("获取用户的收藏夹主页失败：%s", err.Error())
-				return 0
+				c.client.logger.Error("获取用户的收藏夹主页失败", "url", creatorCollectionLink, "err", err)
+				return 0, err
 			}
 			titleTag := doc.Find(selector).First()
 			if titleTag.Size() == 1 {
@@ -249,11 +256,11 @@ func (c *Collection) GetAnswersNum() int {
 			} else {
 				// 本页没找到，下一页
 				if doc.Find("div.border-pager").Size() == 0 {
-					return 0
+					return 0, nil
 				} else {
 					pages := getTotalPages(doc)
 					if page == pages {
-						return 0
+						return 0, nil
 					}
 					page++
 				}
@@ -261,21 +268,21 @@ func (c *Collection) GetAnswersNum() int {
 		}
 	} else {
 		selector := "#zh-list-answer-wrap div.zm-item-fav"
-		rv = c.Doc().Find(selector).Size()
+		rv = firstPage.Find(selector).Size()
 		currentPage := 2
 		for currentPage <= totalPages {
 			link := fmt.Sprintf("%s?page=%d", c.Link, currentPage)
-			doc, err := newDocumentFromURL(link)
+			doc, err := c.client.newDocumentFromURL(ctx, link)
 			if err != nil {
-				logger.Error("解析页面失败：%s, %s", link, err.Error())
-				return 0
+				c.client.logger.Error("解析页面失败", "url", link, "err", err)
+				return 0, err
 			}
 			rv += doc.Find(selector).Size()
 			currentPage++
 		}
 	}
 	c.setField("answer-num", rv)
-	return rv
+	return rv, nil
 }
 
 // GetCommentsNum 返回评论数量
@@ -291,92 +298,98 @@ func (c *Collection) GetCommentsNum() int {
 	return rv
 }
 
-func (c *Collection) String() string {
-	return fmt.Sprintf("<Collection: %s - %s>", c.GetName(), c.Link)
+// GetComments 返回收藏夹的所有评论
+func (c *Collection) GetComments() []*Comment {
+	return c.GetCommentsN(-1)
 }
 
-func ajaxGetFollowers(link string, xsrf string, total int) ([]*User, error) {
-	if total == 0 {
-		return nil, nil
-	}
+// GetCommentsN 返回前 n 条评论，如果 n < 0，返回所有评论
+func (c *Collection) GetCommentsN(n int) []*Comment {
+	comments, _ := c.GetCommentsNCtx(context.Background(), n)
+	return comments
+}
 
-	var (
-		offset     = 0
-		gotDataNum = pageSize
-		initCap    = total
-	)
+// GetCommentsNCtx 同 GetCommentsN，ctx 取消或超时后停止翻页并返回错误
+func (c *Collection) GetCommentsNCtx(ctx context.Context, n int) ([]*Comment, error) {
+	return c.CommentsIter(ctx).take(n)
+}
 
-	if initCap < 0 {
-		initCap = pageSize
-	}
-	users := make([]*User, 0, initCap)
+// CommentsIter 返回一个逐个获取评论的 Iterator
+func (c *Collection) CommentsIter(ctx context.Context) *CommentIterator {
+	return c.client.commentsIter(ctx, "collection.comments", c.Page, "favlists", func() int {
+		return idFromLink(c.Link)
+	})
+}
 
-	form := url.Values{}
-	form.Set("_xsrf", xsrf)
+func (c *Collection) String() string {
+	return fmt.Sprintf("<Collection: %s - %s>", c.GetName(), c.Link)
+}
+
+// followersIter 返回逐个获取问题或收藏夹的关注者的 Iterator，link 是关注者页面，先载入 page 以获取 _xsrf
+func (c *Client) followersIter(ctx context.Context, kind string, page *Page, link string) *UserIterator {
+	return newIterator(ctx, kind, page.Link, func(ctx context.Context, cursor *Cursor) ([]*User, bool, error) {
+		if _, err := page.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
 
-	for gotDataNum == pageSize {
-		form.Set("offset", strconv.Itoa(offset))
-		doc, dataNum, err := newDocByNormalAjax(link, form)
+		form := url.Values{}
+		form.Set("_xsrf", page.GetXSRF())
+		form.Set("offset", strconv.Itoa(cursor.Offset))
+		doc, dataNum, err := c.newDocByNormalAjax(ctx, link, form)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
+		var users []*User
 		doc.Find("div.zm-profile-card").Each(func(index int, sel *goquery.Selection) {
-			thisUser := newUserFromSelector(sel)
-			users = append(users, thisUser)
+			users = append(users, c.newUserFromSelector(sel))
 		})
 
-		if total > 0 && len(users) >= total {
-			return users[:total], nil
-		}
-
-		gotDataNum = dataNum
-		offset += gotDataNum
-	}
-	return users, nil
+		return users, dataNum == pageSize, nil
+	})
 }
 
-func newDocByNormalAjax(link string, form url.Values) (*goquery.Document, int, error) {
+func (c *Client) newDocByNormalAjax(ctx context.Context, link string, form url.Values) (*goquery.Document, int, error) {
 	gotDataNum := 0
-	body := strings.NewReader(form.Encode())
-	resp, err := gSession.Ajax(link, body, link)
+	result := normalAjaxResult{}
+	err := c.ajaxJSON(ctx, link, form, link, &result)
 	if err != nil {
-		logger.Error("查询关注的话题失败, 链接：%s, 参数：%s，错误：%s", link, form.Encode(), err.Error())
+		c.logger.Error("Ajax 请求失败", "url", link, "form", form.Encode(), "err", err)
 		return nil, gotDataNum, err
 	}
 
-	defer resp.Body.Close()
-	result := normalAjaxResult{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		logger.Error("解析返回值 json 失败：%s", err.Error())
-		return nil, gotDataNum, err
+	if len(result.Msg) < 2 {
+		return nil, gotDataNum, wrapError(ErrParse, "%s 返回的数据格式不正确", link)
+	}
+	num, ok1 := result.Msg[0].(float64)
+	topicsHtml, ok2 := result.Msg[1].(string)
+	if !ok1 || !ok2 {
+		return nil, gotDataNum, wrapError(ErrParse, "%s 返回的数据格式不正确", link)
 	}
 
-	topicsHtml := result.Msg[1].(string)
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(topicsHtml))
 	if err != nil {
-		logger.Error("解析返回的 HTML 失败：%s", err.Error())
-		return nil, gotDataNum, err
+		c.logger.Error("解析返回的 HTML 失败", "url", link, "err", err)
+		return nil, gotDataNum, wrapError(ErrParse, "%s", err.Error())
 	}
-	gotDataNum = int(result.Msg[0].(float64))
-	return doc, gotDataNum, err
+	gotDataNum = int(num)
+	return doc, gotDataNum, nil
 }
 
-func getQuestionsFromDoc(doc *goquery.Document) []*Question {
+func (c *Client) getQuestionsFromDoc(doc *goquery.Document) []*Question {
 	questions := make([]*Question, 0, pageSize)
 	items := doc.Find("div#zh-list-answer-wrap").Find("h2.zm-item-title")
 	items.Each(func(index int, sel *goquery.Selection) {
 		a := sel.Find("a")
 		qTitle := strip(a.Text())
 		qHref, _ := a.Attr("href")
-		thisQuestion := NewQuestion(makeZhihuLink(qHref), qTitle)
+		thisQuestion := newQuestion(c, c.makeZhihuLink(qHref), qTitle)
 		questions = append(questions, thisQuestion)
 	})
 	return questions
 }
 
-func getAnswersFromDoc(doc *goquery.Document) []*Answer {
+func (c *Client) getAnswersFromDoc(doc *goquery.Document) []*Answer {
 	var answers []*Answer
 	var lastQuestion *Question
 
@@ -386,7 +399,7 @@ func getAnswersFromDoc(doc *goquery.Document) []*Answer {
 		if contentTag.Size() == 0 {
 			// 回答被建议修改
 			reason := strip(sel.Find("div.answer-status").Text())
-			logger.Warn("忽略一个问题，原因：%s", reason)
+			c.logger.Warn("忽略一个问题", "reason", reason)
 			return
 		}
 
@@ -397,20 +410,21 @@ func getAnswersFromDoc(doc *goquery.Document) []*Answer {
 		if qTag := sel.Find("h2.zm-item-title").Find("a"); qTag.Size() > 0 {
 			qTitle := strip(qTag.Text())
 			qHref, _ := qTag.Attr("href")
-			thisQuestion = NewQuestion(makeZhihuLink(qHref), qTitle)
+			thisQuestion = newQuestion(c, c.makeZhihuLink(qHref), qTitle)
 			lastQuestion = thisQuestion
 		} else {
 			thisQuestion = lastQuestion
 		}
 
 		// 答主
-		author := newUserFromAnswerAuthorTag(sel.Find("div.zm-item-answer-author-info"))
+		author := c.newUserFromAnswerAuthorTag(sel.Find("div.zm-item-answer-author-info"))
 
 		answerHref, _ := contentTag.Attr("data-entry-url")
 		voteText, _ := sel.Find("a.zm-item-vote-count").Attr("data-votecount")
 		vote, _ := strconv.Atoi(voteText)
-		thisAnswer := NewAnswer(makeZhihuLink(answerHref), thisQuestion, author)
+		thisAnswer := newAnswer(c, c.makeZhihuLink(answerHref), thisQuestion, author)
 		thisAnswer.setUpvote(vote)
+		thisAnswer.setTimes(sel, time.Now())
 
 		answers = append(answers, thisAnswer)
 	})
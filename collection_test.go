@@ -0,0 +1,84 @@
+package zhihu
+
+import (
+	"testing"
+)
+
+func newReplayCollection(t *testing.T) *Collection {
+	collection, err := newReplayClient().Collection("https://www.zhihu.com/collection/19653044", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := collection.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+	return collection
+}
+
+func Test_Collection(t *testing.T) {
+	collection := newReplayCollection(t)
+
+	if name := collection.GetName(); name != "恩恩恩 大力一点，不要停～" {
+		t.Errorf("GetName() returns %s", name)
+	}
+
+	creator := collection.GetCreator()
+	if creator.GetUserID() != "李阳良" || creator.Link != "https://www.zhihu.com/people/leonyoung" {
+		t.Errorf("GetCreator() returns error result: %s", creator)
+	}
+
+	ioMap := map[string][2]int{
+		"GetFollowersNum": {collection.GetFollowersNum(), 7516},
+		"GetCommentsNum":  {collection.GetCommentsNum(), 13},
+		"GetQuestionsNum": {collection.GetQuestionsNum(), 3},
+		"GetAnswersNum":   {collection.GetAnswersNum(), 4},
+	}
+	for method, value := range ioMap {
+		if value[0] != value[1] {
+			t.Errorf("%s() returns %d, want %d", method, value[0], value[1])
+		}
+	}
+}
+
+func Test_CollectionGetQuestions(t *testing.T) {
+	questions := newReplayCollection(t).GetQuestions()
+	if len(questions) != 3 {
+		t.Fatalf("GetQuestions() returns %d questions, want 3", len(questions))
+	}
+	if questions[1].GetTitle() != "有哪些值得一看的纪录片？" || questions[1].Link != "https://www.zhihu.com/question/20034893" {
+		t.Errorf("GetQuestions() returns error result: %s", questions[1])
+	}
+}
+
+func Test_CollectionGetAnswers(t *testing.T) {
+	answers := newReplayCollection(t).GetAnswers()
+
+	// 第 4 个回答被建议修改，会被跳过
+	if len(answers) != 3 {
+		t.Fatalf("GetAnswers() returns %d answers, want 3", len(answers))
+	}
+
+	second := answers[1]
+	if second.Link != "https://www.zhihu.com/question/19570036/answer/19917012" || second.GetUpvote() != 2310 {
+		t.Errorf("GetAnswers() returns error result: %s, upvote %d", second.Link, second.GetUpvote())
+	}
+	if second.GetQuestion() != answers[0].GetQuestion() {
+		t.Errorf("answers of the same question should share the Question object")
+	}
+	if second.GetAuthor().GetUserID() != "张佳玮" || !answers[2].GetAuthor().IsAnonymous() {
+		t.Errorf("GetAnswers() returns error authors: %s, %s", second.GetAuthor(), answers[2].GetAuthor())
+	}
+}
+
+func Test_CollectionGetFollowers(t *testing.T) {
+	followers := newReplayCollection(t).GetFollowersN(5)
+	if len(followers) != 2 {
+		t.Fatalf("GetFollowersN(5) returns %d users, want 2", len(followers))
+	}
+
+	user := followers[0]
+	if user.GetUserID() != "张佳玮" || user.GetBio() != "写字的" || user.GetFollowersNum() != 1170345 || user.GetAgreeNum() != 3364321 {
+		t.Errorf("GetFollowersN returns error result: %s, bio %s, followers %d, agree %d",
+			user, user.GetBio(), user.GetFollowersNum(), user.GetAgreeNum())
+	}
+}
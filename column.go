@@ -0,0 +1,221 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+var (
+	reColumnSlug = regexp.MustCompile(`^/([A-Za-z0-9_\-]+)$`)
+	reArticleID  = regexp.MustCompile(`^/p/([0-9]+)$`)
+)
+
+// Column 是一个知乎专栏，如 https://zhuanlan.zhihu.com/pythoner。
+// 专栏的页面是用 JavaScript 渲染的，数据都来自专栏的 JSON 接口，第一次调用 GetXXX 时载入
+type Column struct {
+	// Link 是该专栏的链接
+	Link string
+
+	// client 是该专栏绑定的 Client
+	client *Client
+
+	// slug 是专栏的个性域名，即链接的最后一部分
+	slug string
+
+	// name 是专栏的名字
+	name string
+
+	data apiResource[columnData]
+}
+
+// columnData 是 /api/columns/{slug} 返回的 JSON
+type columnData struct {
+	Name           string   `json:"name"`
+	Slug           string   `json:"slug"`
+	Description    string   `json:"description"`
+	FollowersCount int      `json:"followersCount"`
+	PostsCount     int      `json:"postsCount"`
+	Creator        *apiUser `json:"creator"`
+}
+
+// Column 创建一个绑定在该 Client 上的专栏对象，link 是专栏的链接，如 https://zhuanlan.zhihu.com/pythoner，
+// name 是专栏的名字，可以为空，需要时再从接口获取。链接不合法时返回 ErrInvalidURL
+func (c *Client) Column(link string, name string) (*Column, error) {
+	m := c.matchColumnURL(link, reColumnSlug)
+	if m == nil || m[1] == "p" {
+		return nil, wrapError(ErrInvalidURL, "专栏链接不正确：%s", link)
+	}
+	return newColumn(c, link, m[1], name), nil
+}
+
+func newColumn(client *Client, link string, slug string, name string) *Column {
+	return &Column{
+		Link:   link,
+		client: client,
+		slug:   slug,
+		name:   name,
+	}
+}
+
+// GetSlug 返回专栏的个性域名，如 pythoner
+func (c *Column) GetSlug() string {
+	return c.slug
+}
+
+// GetName 返回专栏的名字
+func (c *Column) GetName() string {
+	if c.name != "" {
+		return c.name
+	}
+	return c.get().Name
+}
+
+// GetDescription 返回专栏的简介
+func (c *Column) GetDescription() string {
+	return c.get().Description
+}
+
+// GetFollowersNum 返回专栏的关注者数量
+func (c *Column) GetFollowersNum() int {
+	return c.get().FollowersCount
+}
+
+// GetArticlesNum 返回专栏的文章数量
+func (c *Column) GetArticlesNum() int {
+	return c.get().PostsCount
+}
+
+// GetCreator 返回专栏的创建者
+func (c *Column) GetCreator() *User {
+	return c.client.newUserFromAPI(c.get().Creator)
+}
+
+// GetArticlesN 返回专栏最新的 n 篇文章，如果 n < 0，返回所有文章
+func (c *Column) GetArticlesN(n int) []*Article {
+	articles, _ := c.GetArticlesNCtx(context.Background(), n)
+	return articles
+}
+
+// GetArticlesNCtx 同 GetArticlesN，ctx 取消或超时后停止翻页并返回错误
+func (c *Column) GetArticlesNCtx(ctx context.Context, n int) ([]*Article, error) {
+	return c.ArticlesIter(ctx).take(n)
+}
+
+// GetArticles 返回专栏的所有文章，最新的在前面
+func (c *Column) GetArticles() []*Article {
+	return c.GetArticlesN(-1)
+}
+
+// ArticlesIter 返回一个逐个获取专栏文章的 Iterator，最新的在前面
+func (c *Column) ArticlesIter(ctx context.Context) *ArticleIterator {
+	return newIterator(ctx, "column.articles", c.Link, func(ctx context.Context, cursor *Cursor) ([]*Article, bool, error) {
+		link := c.client.makeColumnLink(fmt.Sprintf("/api/columns/%s/posts?limit=%d&offset=%d", c.slug, pageSize, cursor.Offset))
+		var result []articleData
+		if err := c.client.getJSON(ctx, link, &result); err != nil {
+			c.client.logger.Error("获取专栏文章失败", "url", link, "err", err)
+			return nil, false, err
+		}
+
+		articles := make([]*Article, 0, len(result))
+		for i := range result {
+			articles = append(articles, c.client.newArticleFromData(&result[i]))
+		}
+		return articles, len(result) == pageSize, nil
+	})
+}
+
+// Refresh 会重新载入专栏的数据
+func (c *Column) Refresh() error {
+	return c.RefreshCtx(context.Background())
+}
+
+// RefreshCtx 同 Refresh，ctx 取消或超时后请求会被中止
+func (c *Column) RefreshCtx(ctx context.Context) error {
+	_, err := c.data.load(ctx, c.client, c.apiLink(), true)
+	return err
+}
+
+// Err 返回最近一次载入数据时的错误，没有出错则返回 nil
+func (c *Column) Err() error {
+	return c.data.Err()
+}
+
+func (c *Column) String() string {
+	return fmt.Sprintf("<Column: %s - %s>", c.GetName(), c.Link)
+}
+
+func (c *Column) apiLink() string {
+	return c.client.makeColumnLink("/api/columns/" + c.slug)
+}
+
+// get 返回专栏的数据，载入失败时返回零值，错误可以通过 Err 获取
+func (c *Column) get() *columnData {
+	data, err := c.data.load(context.Background(), c.client, c.apiLink(), false)
+	if err != nil {
+		return &columnData{}
+	}
+	return data
+}
+
+// matchColumnURL 判断 link 是否是专栏的链接，并且路径匹配 rePath，返回匹配的结果
+func (c *Client) matchColumnURL(link string, rePath *regexp.Regexp) []string {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.RawQuery != "" || u.Fragment != "" {
+		return nil
+	}
+	path, ok := c.session.columnPath(u)
+	if !ok {
+		return nil
+	}
+	return rePath.FindStringSubmatch(path)
+}
+
+// columnLinkFromHref 把页面上专栏、专栏文章的链接转换成完整的链接，它们通常已经是完整的链接了
+func (c *Client) columnLinkFromHref(href string) string {
+	if u, err := url.Parse(href); err == nil && u.IsAbs() {
+		return href
+	}
+	return c.makeColumnLink(href)
+}
+
+// apiResource 是从 JSON 接口载入的数据，同 Page 一样惰性载入，可以在多个 goroutine 里同时使用
+type apiResource[T any] struct {
+	mu   sync.Mutex
+	data *T
+	err  error
+}
+
+// load 载入数据，refresh 为 false 时如果已经载入过就直接返回。载入时持有锁，所以同时只会有一个请求
+func (r *apiResource[T]) load(ctx context.Context, client *Client, link string, refresh bool) (*T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data != nil && !refresh {
+		return r.data, nil
+	}
+
+	data := new(T)
+	if err := client.getJSON(ctx, link, data); err != nil {
+		client.logger.Error("请求失败", "url", link, "err", err)
+		r.err = err
+		return nil, err
+	}
+	r.data, r.err = data, nil
+	return data, nil
+}
+
+// set 设置已经从列表接口得到的数据，避免再次请求
+func (r *apiResource[T]) set(data *T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data = data
+}
+
+// Err 返回最近一次载入时的错误
+func (r *apiResource[T]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
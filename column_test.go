@@ -0,0 +1,110 @@
+package zhihu
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newReplayArticle(t *testing.T) *Article {
+	article, err := newReplayClient().Article("https://zhuanlan.zhihu.com/p/20761239", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := article.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+	return article
+}
+
+func Test_Column(t *testing.T) {
+	column, err := newReplayClient().Column("https://zhuanlan.zhihu.com/pythoner", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := column.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+
+	if column.GetSlug() != "pythoner" || column.GetName() != "Python 程序员" || column.GetDescription() != "给 Python 程序员的专栏" {
+		t.Errorf("Column returns error result: %s", column)
+	}
+	if column.GetFollowersNum() != 40813 || column.GetArticlesNum() != 21 {
+		t.Errorf("Column returns error nums: followers %d, articles %d", column.GetFollowersNum(), column.GetArticlesNum())
+	}
+	if creator := column.GetCreator(); creator.GetUserID() != "松鼠奥利奥" || creator.Link != "https://www.zhihu.com/people/sun-shu-ao-li-ao" {
+		t.Errorf("GetCreator() returns error result: %s", creator)
+	}
+
+	articles := column.GetArticles()
+	if len(articles) != 21 {
+		t.Fatalf("GetArticles() returns %d articles, want 21", len(articles))
+	}
+	if a := articles[0]; a.GetID() != 20761239 || a.Link != "https://zhuanlan.zhihu.com/p/20761239" || a.GetTitle() != "用 Python 写一个知乎爬虫" || a.GetLikesNum() != 100 {
+		t.Errorf("articles[0] returns error result: %s", a)
+	}
+	if a := articles[20]; a.GetID() != 20741239 || a.GetColumn().GetSlug() != "pythoner" {
+		t.Errorf("articles[20] returns error result: %s", a)
+	}
+
+	if _, err := newReplayClient().Column("https://zhuanlan.zhihu.com/p", ""); err == nil {
+		t.Error("Column(/p) should return error")
+	}
+}
+
+func Test_Article(t *testing.T) {
+	article := newReplayArticle(t)
+
+	if article.GetTitle() != "用 Python 写一个知乎爬虫" || article.GetLikesNum() != 100 || article.GetCommentsNum() != 2 {
+		t.Errorf("Article returns error result: %s, likes %d, comments %d", article, article.GetLikesNum(), article.GetCommentsNum())
+	}
+	if author := article.GetAuthor(); author.GetUserID() != "松鼠奥利奥" {
+		t.Errorf("GetAuthor() returns error result: %s", author)
+	}
+	if column := article.GetColumn(); column == nil || column.Link != "https://zhuanlan.zhihu.com/pythoner" || column.GetName() != "Python 程序员" {
+		t.Errorf("GetColumn() returns error result: %v", column)
+	}
+	if expected := time.Date(2016, 4, 28, 2, 0, 0, 0, time.UTC); !article.GetPublishedTime().Equal(expected) {
+		t.Errorf("GetPublishedTime() returns %s, want %s", article.GetPublishedTime(), expected)
+	}
+
+	topics := article.GetTopics()
+	if len(topics) != 2 || topics[1].GetName() != "网络爬虫" || topics[1].Link != "https://www.zhihu.com/topic/19559424" {
+		t.Errorf("GetTopics() returns error result: %v", topics)
+	}
+
+	comments := article.GetComments()
+	if len(comments) != 2 {
+		t.Fatalf("GetComments() returns %d comments, want 2", len(comments))
+	}
+	if reply := comments[1]; reply.ID != 1002 || reply.ReplyTo == nil || reply.ReplyTo.GetUserID() != "黄继新" {
+		t.Errorf("comments[1] returns error result: %+v", reply)
+	}
+}
+
+func Test_ArticleExport(t *testing.T) {
+	article := newReplayArticle(t)
+	dir := t.TempDir()
+
+	if err := article.ToMarkdown(filepath.Join(dir, "article")); err != nil {
+		t.Fatalf("ToMarkdown returns error: %s", err.Error())
+	}
+	md, _ := ioutil.ReadFile(filepath.Join(dir, "article.md"))
+	for _, expected := range []string{"用 Python 写一个知乎爬虫", "https://zhuanlan.zhihu.com/p/20761239", "**第一段**"} {
+		if !strings.Contains(string(md), expected) {
+			t.Errorf("ToMarkdown should contain %s, got:\n%s", expected, md)
+		}
+	}
+
+	if err := article.ToHtml(filepath.Join(dir, "article")); err != nil {
+		t.Fatalf("ToHtml returns error: %s", err.Error())
+	}
+	page, _ := ioutil.ReadFile(filepath.Join(dir, "article.html"))
+	for _, expected := range []string{"<h1>用 Python 写一个知乎爬虫</h1>", `href="https://www.zhihu.com/people/sun-shu-ao-li-ao"`, "<b>第一段</b>"} {
+		if !strings.Contains(string(page), expected) {
+			t.Errorf("ToHtml should contain %s, got:\n%s", expected, page)
+		}
+	}
+}
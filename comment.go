@@ -0,0 +1,119 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Comment 是回答、问题或收藏夹下的一条评论
+type Comment struct {
+	// ID 是评论的数字 ID
+	ID int
+
+	// Author 是评论的作者，匿名用户是 ANONYMOUS，已注销的用户是 ID 为“知乎用户”、没有链接的 User
+	Author *User
+
+	// ReplyTo 是这条评论回复的用户，不是回复时为 nil
+	ReplyTo *User
+
+	// Content 是评论的内容，HTML 格式
+	Content string
+
+	// CreatedAt 是评论的时间
+	CreatedAt time.Time
+
+	// Likes 是评论的点赞数
+	Likes int
+
+	// Deleted 表示评论已被删除，此时 Content 为空
+	Deleted bool
+}
+
+func (c *Comment) String() string {
+	return fmt.Sprintf("<Comment: %d - %s>", c.ID, c.Author.GetUserID())
+}
+
+// commentListResult 是评论接口返回的 JSON，如 /r/answers/29734410/comments?page=1
+type commentListResult struct {
+	Paging struct {
+		PerPage     int `json:"perPage"`
+		TotalCount  int `json:"totalCount"`
+		CurrentPage int `json:"currentPage"`
+	} `json:"paging"`
+	Data []commentData `json:"data"`
+}
+
+// commentData 是接口返回的一条评论，专栏文章的评论接口也是这个格式
+type commentData struct {
+	ID            int      `json:"id"`
+	Content       string   `json:"content"`
+	CreatedTime   string   `json:"createdTime"`
+	LikesCount    int      `json:"likesCount"`
+	IsDelete      bool     `json:"isDelete"`
+	Author        *apiUser `json:"author"`
+	InReplyToUser *apiUser `json:"inReplyToUser"`
+}
+
+// apiUser 是 JSON 接口里的用户，如评论的作者、专栏文章的作者，已注销的用户没有 slug
+type apiUser struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	IsAnonymous bool   `json:"isAnonymous"`
+}
+
+// commentsIter 返回逐个获取评论的 Iterator，resource 是 answers, questions 或 favlists，id 是对应的数字 ID
+func (c *Client) commentsIter(ctx context.Context, kind string, page *Page, resource string, id func() int) *CommentIterator {
+	return newIterator(ctx, kind, page.Link, func(ctx context.Context, cursor *Cursor) ([]*Comment, bool, error) {
+		if _, err := page.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
+
+		link := c.makeZhihuLink(fmt.Sprintf("/r/%s/%d/comments?page=%d", resource, id(), cursor.Page+1))
+		result := commentListResult{}
+		if err := c.getJSON(ctx, link, &result); err != nil {
+			c.logger.Error("获取评论失败", "url", link, "err", err)
+			return nil, false, err
+		}
+
+		comments := make([]*Comment, 0, len(result.Data))
+		for _, data := range result.Data {
+			comments = append(comments, c.newComment(data))
+		}
+
+		paging := result.Paging
+		more := len(comments) > 0 && (cursor.Page+1)*paging.PerPage < paging.TotalCount
+		return comments, more, nil
+	}).concurrent(c.session.concurrency)
+}
+
+func (c *Client) newComment(data commentData) *Comment {
+	comment := &Comment{
+		ID:      data.ID,
+		Author:  c.newUserFromAPI(data.Author),
+		Content: data.Content,
+		Likes:   data.LikesCount,
+		Deleted: data.IsDelete,
+	}
+	if data.InReplyToUser != nil {
+		comment.ReplyTo = c.newUserFromAPI(data.InReplyToUser)
+	}
+	comment.CreatedAt, _ = time.Parse(time.RFC3339, data.CreatedTime)
+	return comment
+}
+
+func (c *Client) newUserFromAPI(author *apiUser) *User {
+	switch {
+	case author != nil && author.IsAnonymous:
+		return ANONYMOUS
+	case author == nil || author.Slug == "":
+		return newUser(c, "", "知乎用户")
+	}
+	return newUser(c, c.makeZhihuLink("/people/"+author.Slug), author.Name)
+}
+
+// idFromLink 返回问题、收藏夹链接最后的数字 ID
+func idFromLink(link string) int {
+	return reMatchInt(link[strings.LastIndex(link, "/")+1:])
+}
@@ -0,0 +1,255 @@
+package zhihu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/persistent-cookiejar"
+)
+
+// 导入的 cookies 没有过期时间时（如 Cookie 请求头），使用这个有效期，否则 cookiejar 不会保存它们
+const importedCookieMaxAge = 30 * 24 * time.Hour
+
+// ImportCookies 导入已有的 cookies（如从浏览器导出的）代替用户名和密码登录，不需要验证码。
+// 导入前会先检查是否已经登录：cookies 无效或已过期时返回 ErrLoginRequired，网络错误等无法判断时返回对应的错误，
+// 这两种情况下当前的 cookies 都不会改变；检查通过后才设置并保存到 cookiejar。传入的 cookies 不会被修改
+func (s *Session) ImportCookies(cookies []*http.Cookie) error {
+	if len(cookies) == 0 {
+		return fmt.Errorf("没有可以导入的 cookies")
+	}
+
+	imported := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		c := *cookie
+		if c.Expires.IsZero() && c.MaxAge == 0 {
+			c.Expires = time.Now().Add(importedCookieMaxAge)
+		}
+		imported = append(imported, &c)
+	}
+
+	// 先在临时的 jar 里检查，确认有效之后才修改当前的 jar，这样失败时当前的 cookies 保持原样
+	trial, err := s.withCookies(imported)
+	if err != nil {
+		return err
+	}
+	ok, err := trial.authenticated()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return wrapError(ErrLoginRequired, "导入的 cookies 无效或已过期")
+	}
+
+	s.setCookies(imported)
+	s.logger.Info("导入 cookies 成功，已经是登录状态")
+	return s.saveCookies()
+}
+
+// withCookies 返回一个使用临时 jar 的 Session 副本，jar 里是当前的 cookies 加上 cookies，
+// 用于在不修改当前 jar 的情况下检查 cookies 是否有效
+func (s *Session) withCookies(cookies []*http.Cookie) (*Session, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	if err != nil {
+		return nil, err
+	}
+
+	trial := *s
+	client := *s.client
+	client.Jar = jar
+	trial.client, trial.jar, trial.cookieStore = &client, jar, nil
+	trial.setCookies(s.jar.AllCookies())
+	trial.setCookies(cookies)
+	return &trial, nil
+}
+
+// CookieStore 是 cookies 的存储后端，用 NewSessionWithCookieStore 创建 Session 时指定，
+// 可以实现成内存、加密文件、key/value 数据库等
+type CookieStore interface {
+	// Load 返回保存的所有 cookies，创建 Session 时调用
+	Load() ([]*http.Cookie, error)
+
+	// Save 保存当前所有的 cookies，登录或导入 cookies 成功后调用
+	Save(cookies []*http.Cookie) error
+}
+
+// MemoryCookieStore 把 cookies 保存在内存中，程序退出后就丢失了，适合只读的运行环境
+type MemoryCookieStore struct {
+	mu      sync.Mutex
+	cookies []*http.Cookie
+}
+
+// Load 实现 CookieStore 接口
+func (store *MemoryCookieStore) Load() ([]*http.Cookie, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return append([]*http.Cookie(nil), store.cookies...), nil
+}
+
+// Save 实现 CookieStore 接口
+func (store *MemoryCookieStore) Save(cookies []*http.Cookie) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.cookies = append([]*http.Cookie(nil), cookies...)
+	return nil
+}
+
+// saveCookies 把 cookies 保存到 CookieStore，没有指定 CookieStore 时保存到 cookiejar 的文件
+func (s *Session) saveCookies() error {
+	if s.cookieStore != nil {
+		return s.cookieStore.Save(s.jar.AllCookies())
+	}
+	return s.jar.Save()
+}
+
+// setCookies 把 cookies 按各自的域名和路径设置到 jar 里，没有域名的 cookies 属于 BaseURL 的 host
+func (s *Session) setCookies(cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		domain := strings.TrimPrefix(cookie.Domain, ".")
+		if domain == "" {
+			domain = s.baseURL.Hostname()
+		}
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		s.jar.SetCookies(&url.URL{Scheme: "https", Host: domain, Path: path}, []*http.Cookie{cookie})
+	}
+}
+
+// ImportCookiesFile 从文件导入 cookies，支持以下格式，会根据内容自动判断：
+// 	1. Netscape 的 cookies.txt 格式，很多浏览器插件和 curl 都可以导出
+// 	2. JSON 数组，如 EditThisCookie 插件导出的格式
+// 	3. Cookie 请求头，形如 "z_c0=xxx; _xsrf=yyy"，可以直接从浏览器的开发者工具复制
+func (s *Session) ImportCookiesFile(filename string) error {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	cookies, err := parseCookies(content)
+	if err != nil {
+		return err
+	}
+	return s.ImportCookies(cookies)
+}
+
+// ImportCookieHeader 从 Cookie 请求头导入 cookies，如 "z_c0=xxx; _xsrf=yyy"
+func (s *Session) ImportCookieHeader(header string) error {
+	return s.ImportCookies(ParseCookieHeader(header))
+}
+
+func parseCookies(content []byte) ([]*http.Cookie, error) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return ParseJSONCookies(bytes.NewReader(trimmed))
+	case bytes.Contains(trimmed, []byte("\t")):
+		return ParseNetscapeCookies(bytes.NewReader(trimmed))
+	default:
+		header := strings.TrimSpace(strings.TrimPrefix(string(trimmed), "Cookie:"))
+		return ParseCookieHeader(header), nil
+	}
+}
+
+// ParseNetscapeCookies 解析 Netscape cookies.txt 格式的 cookies，每行 7 个字段，以 tab 分隔：
+// domain, include subdomains, path, secure, expires, name, value
+func ParseNetscapeCookies(r io.Reader) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			httpOnly = true
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, wrapError(ErrParse, "cookies.txt 第 %d 行格式不正确", lineNo)
+		}
+
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, scanner.Err()
+}
+
+// jsonCookie 是浏览器插件导出的 JSON 格式的 cookie
+type jsonCookie struct {
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Domain         string  `json:"domain"`
+	Path           string  `json:"path"`
+	Secure         bool    `json:"secure"`
+	HttpOnly       bool    `json:"httpOnly"`
+	ExpirationDate float64 `json:"expirationDate"`
+	Expires        float64 `json:"expires"`
+}
+
+// ParseJSONCookies 解析 JSON 数组格式的 cookies，如 EditThisCookie 插件导出的格式：
+// [{"domain": ".zhihu.com", "name": "z_c0", "value": "xxx", "path": "/", "expirationDate": 1500000000}]
+func ParseJSONCookies(r io.Reader) ([]*http.Cookie, error) {
+	var items []jsonCookie
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, wrapError(ErrParse, "解析 JSON 格式的 cookies 失败：%s", err.Error())
+	}
+
+	cookies := make([]*http.Cookie, 0, len(items))
+	for _, item := range items {
+		cookie := &http.Cookie{
+			Name:     item.Name,
+			Value:    item.Value,
+			Domain:   item.Domain,
+			Path:     item.Path,
+			Secure:   item.Secure,
+			HttpOnly: item.HttpOnly,
+		}
+		expires := item.ExpirationDate
+		if expires == 0 {
+			expires = item.Expires
+		}
+		if expires > 0 {
+			cookie.Expires = time.Unix(int64(expires), 0)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, nil
+}
+
+// ParseCookieHeader 解析 Cookie 请求头，如 "z_c0=xxx; _xsrf=yyy"。
+// 返回的 cookies 没有域名，导入时属于 Session 的 BaseURL 的 host，见 Session.SetBaseURL
+func ParseCookieHeader(header string) []*http.Cookie {
+	req := http.Request{Header: http.Header{"Cookie": {header}}}
+	cookies := req.Cookies()
+	for _, cookie := range cookies {
+		cookie.Path = "/"
+	}
+	return cookies
+}
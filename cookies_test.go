@@ -0,0 +1,112 @@
+package zhihu
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_parseCookies(t *testing.T) {
+	ioMap := map[string]string{
+		"# Netscape HTTP Cookie File\n" +
+			".zhihu.com\tTRUE\t/\tFALSE\t1900000000\tz_c0\tnetscape\n" +
+			"#HttpOnly_.zhihu.com\tTRUE\t/\tTRUE\t0\t_xsrf\tabc\n": "netscape",
+		`[{"domain": ".zhihu.com", "name": "z_c0", "value": "json", "path": "/", "expirationDate": 1900000000.5},
+		  {"domain": ".zhihu.com", "name": "_xsrf", "value": "abc", "path": "/"}]`: "json",
+		"Cookie: z_c0=header; _xsrf=abc": "header",
+	}
+
+	for content, value := range ioMap {
+		cookies, err := parseCookies([]byte(content))
+		if err != nil {
+			t.Fatalf("parseCookies(%s) returns error: %s", value, err.Error())
+		}
+		if len(cookies) != 2 {
+			t.Fatalf("parseCookies(%s) returns %d cookies, want 2", value, len(cookies))
+		}
+		domain := ".zhihu.com"
+		if value == "header" {
+			domain = "" // Cookie 请求头没有域名，导入时使用 Session 的 BaseURL
+		}
+		if cookies[0].Name != "z_c0" || cookies[0].Value != value || cookies[0].Domain != domain {
+			t.Errorf("parseCookies(%s) returns error result: %+v", value, cookies[0])
+		}
+		if value != "header" && cookies[0].Expires.Unix() != 1900000000 {
+			t.Errorf("parseCookies(%s) returns error expires: %v", value, cookies[0].Expires)
+		}
+	}
+}
+
+func Test_NewSessionWithCookieStore(t *testing.T) {
+	store := new(MemoryCookieStore)
+	store.Save(ParseCookieHeader("z_c0=token; _xsrf=abc"))
+
+	s, err := NewSessionWithCookieStore(store)
+	if err != nil {
+		t.Fatalf("NewSessionWithCookieStore returns error: %s", err.Error())
+	}
+
+	link, _ := url.Parse("https://www.zhihu.com/")
+	if got := len(s.client.Jar.Cookies(link)); got != 2 {
+		t.Fatalf("session loads %d cookies from store, want 2", got)
+	}
+
+	store.Save(nil)
+	if err := s.saveCookies(); err != nil {
+		t.Fatalf("saveCookies returns error: %s", err.Error())
+	}
+	if cookies, _ := store.Load(); len(cookies) != 2 {
+		t.Errorf("saveCookies saves %d cookies to store, want 2", len(cookies))
+	}
+}
+
+func Test_ImportCookiesRollback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/settings/profile" {
+			http.Redirect(w, r, "/?next=%2Fsettings%2Fprofile", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	s, _ := NewSessionWithCookieStore(nil)
+	s.SetBaseURL(server.URL)
+	s.setCookies(ParseCookieHeader("_xsrf=old"))
+
+	invalid := ParseCookieHeader("z_c0=invalid")
+	if err := s.ImportCookies(invalid); !errors.Is(err, ErrLoginRequired) {
+		t.Fatalf("ImportCookies returns %v, want ErrLoginRequired", err)
+	}
+	if !invalid[0].Expires.IsZero() {
+		t.Errorf("ImportCookies modifies the given cookies: %v", invalid[0].Expires)
+	}
+
+	link, _ := url.Parse(server.URL)
+	cookies := s.client.Jar.Cookies(link)
+	if len(cookies) != 1 || cookies[0].Name != "_xsrf" || cookies[0].Value != "old" {
+		t.Errorf("cookies after rejected import: %v, want only _xsrf=old", cookies)
+	}
+}
+
+func Test_ImportCookiesNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	link := server.URL
+	server.Close() // 请求会因为连接失败而出错
+
+	s, _ := NewSessionWithCookieStore(nil)
+	s.SetBaseURL(link)
+	s.SetRetryPolicy(nil)
+	s.setCookies(ParseCookieHeader("_xsrf=old"))
+
+	err := s.ImportCookies(ParseCookieHeader("z_c0=valid"))
+	if err == nil || errors.Is(err, ErrLoginRequired) {
+		t.Fatalf("ImportCookies with network error returns %v, want the network error", err)
+	}
+
+	u, _ := url.Parse(link)
+	cookies := s.client.Jar.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != "_xsrf" || cookies[0].Value != "old" {
+		t.Errorf("cookies after failed import: %v, want only _xsrf=old", cookies)
+	}
+}
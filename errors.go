@@ -0,0 +1,72 @@
+package zhihu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 可以用 errors.Is 判断的错误类型，API 返回的错误都会包装其中之一（如果能判断出原因的话）
+var (
+	// ErrNotFound 表示页面不存在，如问题被删除、用户不存在
+	ErrNotFound = errors.New("zhihu: 页面不存在")
+
+	// ErrLoginRequired 表示需要登录，或者登录已经失效
+	ErrLoginRequired = errors.New("zhihu: 需要登录")
+
+	// ErrRateLimited 表示请求过于频繁，被知乎限制了
+	ErrRateLimited = errors.New("zhihu: 请求过于频繁")
+
+	// ErrBudgetExhausted 表示 RateLimiter 设置的当天请求数已经用完了，请求没有发出
+	ErrBudgetExhausted = errors.New("zhihu: 今天的请求数已经用完")
+
+	// ErrParse 表示无法解析服务器返回的内容，通常是知乎改版了
+	ErrParse = errors.New("zhihu: 解析失败")
+
+	// ErrInvalidURL 表示传入的链接不合法
+	ErrInvalidURL = errors.New("zhihu: 链接不合法")
+)
+
+// wrapError 给 kind 附加上下文信息，返回的错误可以用 errors.Is(err, kind) 判断
+func wrapError(kind error, format string, a ...interface{}) error {
+	return fmt.Errorf("%w: %s", kind, fmt.Sprintf(format, a...))
+}
+
+// isContextError 判断 err 是否是因为 ctx 被取消或超时
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// checkResponse 根据响应的状态码和跳转情况判断请求是否成功，失败时关闭 resp.Body 并返回对应的错误
+func checkResponse(resp *http.Response) error {
+	var err error
+	link := resp.Request.URL.String()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		err = wrapError(ErrNotFound, "%s", link)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err = wrapError(ErrLoginRequired, "%s, StatusCode = %d", link, resp.StatusCode)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err = wrapError(ErrRateLimited, "%s", link)
+	case resp.StatusCode >= http.StatusBadRequest:
+		err = fmt.Errorf("请求 %s 失败，StatusCode = %d", link, resp.StatusCode)
+	case isLoginRedirect(resp):
+		// 没有登录时，会跳转到 https://www.zhihu.com/?next=%2Fsettings%2Fprofile 这样的页面
+		err = wrapError(ErrLoginRequired, "%s", link)
+	}
+
+	if err != nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func isLoginRedirect(resp *http.Response) bool {
+	if resp.Request.Response == nil {
+		return false // 没有发生跳转
+	}
+	u := resp.Request.URL
+	return u.Query().Get("next") != "" || strings.Contains(u.Path, "/login") || strings.Contains(u.Path, "/signin")
+}
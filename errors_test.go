@@ -0,0 +1,114 @@
+package zhihu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_checkResponse(t *testing.T) {
+	ioMap := map[int]error{
+		http.StatusOK:              nil,
+		http.StatusNotFound:        ErrNotFound,
+		http.StatusForbidden:       ErrLoginRequired,
+		http.StatusTooManyRequests: ErrRateLimited,
+	}
+
+	for status, expected := range ioMap {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		client := NewClient(nil)
+		_, err := client.newDocumentFromURL(context.Background(), server.URL)
+		if !errors.Is(err, expected) || (expected == nil && err != nil) {
+			t.Errorf("status %d: got error %v, want %v", status, err, expected)
+		}
+		server.Close()
+	}
+}
+
+func Test_loginRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/people/jixin" {
+			http.Redirect(w, r, "/?next=%2Fpeople%2Fjixin", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	user := newUser(NewClient(nil), server.URL+"/people/jixin", "")
+	if bio := user.GetBio(); bio != "" {
+		t.Errorf("GetBio returns %q on failure", bio)
+	}
+	if !errors.Is(user.Err(), ErrLoginRequired) {
+		t.Errorf("Err returns %v, want ErrLoginRequired", user.Err())
+	}
+}
+
+func Test_getterCtxErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(nil)
+	ctx := context.Background()
+	if bio, err := newUser(client, server.URL+"/people/jixin", "").GetBioCtx(ctx); bio != "" || !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBioCtx returns %q, %v, want ErrNotFound", bio, err)
+	}
+	if num, err := newQuestion(client, server.URL+"/question/41171543", "").GetAnswersNumCtx(ctx); num != 0 || !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetAnswersNumCtx returns %d, %v, want ErrNotFound", num, err)
+	}
+	if created, err := newQuestion(client, server.URL+"/question/41171543", "").GetCreatedTimeCtx(ctx); !created.IsZero() || !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetCreatedTimeCtx returns %s, %v, want ErrNotFound", created, err)
+	}
+	if questions, err := newCollection(client, server.URL+"/collection/19653044", "", nil).GetQuestionsNCtx(ctx, -1); questions != nil || !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetQuestionsNCtx returns %v, %v, want ErrNotFound", questions, err)
+	}
+}
+
+func Test_exportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	answer := newAnswer(NewClient(nil), server.URL+"/question/41171543/answer/88475539", nil, nil)
+	if err := answer.ToMarkdown(filepath.Join(dir, "answer")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Answer.ToMarkdown returns %v, want ErrNotFound", err)
+	}
+	if err := answer.ToHtml(filepath.Join(dir, "answer")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Answer.ToHtml returns %v, want ErrNotFound", err)
+	}
+
+	session, _ := NewSessionWithCookieStore(nil)
+	if err := session.SetColumnBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	article := newArticle(NewClient(session), server.URL+"/p/20761239", 20761239, "")
+	if err := article.ToMarkdown(filepath.Join(dir, "article")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Article.ToMarkdown returns %v, want ErrNotFound", err)
+	}
+	if err := article.ToHtml(filepath.Join(dir, "article")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Article.ToHtml returns %v, want ErrNotFound", err)
+	}
+
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("export should not write any file when loading fails, got %d files", len(files))
+	}
+}
+
+func Test_invalidURL(t *testing.T) {
+	client := NewClient(nil)
+	if _, err := client.Question("https://www.zhihu.com/question/abc", ""); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Question returns %v, want ErrInvalidURL", err)
+	}
+	if _, err := client.Topic("https://www.zhihu.com/people/jixin", ""); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Topic returns %v, want ErrInvalidURL", err)
+	}
+}
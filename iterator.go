@@ -0,0 +1,284 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// Cursor 记录一个分页列表遍历到的位置，可以序列化成 JSON 保存到磁盘，
+// 程序崩溃或重启后用 Iterator.Seek 从这个位置继续，不需要从头开始
+type Cursor struct {
+	// Kind 是列表的类型，如 user.followers, collection.answers
+	Kind string `json:"kind"`
+
+	// URL 是列表所属的用户、问题或收藏夹的链接
+	URL string `json:"url"`
+
+	// Page 是下一次请求的页码，从 0 开始
+	Page int `json:"page"`
+
+	// Offset 是下一次 Ajax 请求的 offset，即之前的页一共返回了多少个元素
+	Offset int `json:"offset"`
+
+	// Skip 是 Page 这一页里已经返回过的元素个数，继续时会跳过它们
+	Skip int `json:"skip,omitempty"`
+
+	// HashID 是用户的 hash ID，获取关注的人、粉丝时用到
+	HashID string `json:"hash_id,omitempty"`
+
+	// Start 是按时间翻页的列表下一次请求的起始位置，0 表示从最新的开始。
+	// 用户动态是最后一条的时间戳，问题日志是最后一条的 ID
+	Start int64 `json:"start,omitempty"`
+
+	// Done 表示列表已经遍历完了
+	Done bool `json:"done,omitempty"`
+}
+
+// pageFetcher 获取 cursor 指向的那一页，返回这一页的元素，以及后面是否还有数据。
+// 需要时可以在 cursor 里记录 HashID 等信息，翻页由 Iterator 负责；按时间翻页的列表把下一页的起始时间记录在 cursor.Start 里
+type pageFetcher[T any] func(ctx context.Context, cursor *Cursor) (items []T, more bool, err error)
+
+// Iterator 逐个返回一个分页列表里的元素，需要时才请求下一页，可以随时停止，适合粉丝、回答很多的情况。
+// 用法：
+//
+//	it := user.FollowersIter(ctx)
+//	for it.Next() {
+//		follower := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// 或者用 range-over-func：
+//
+//	for follower := range it.All() {
+//		...
+//	}
+//
+// 遍历过程中可以随时用 Cursor 保存位置，之后新建一个同样的 Iterator，用 Seek 继续。
+// 按页码翻页的列表会按照 Session.SetConcurrency 的设置同时请求后面的几页，返回的顺序不变。Iterator 不是并发安全的
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    pageFetcher[T]
+	workers  int    // 同时请求的页数，只用于按页码翻页、各页互不依赖的列表
+	next     Cursor // 下一次请求的位置
+	current  Cursor // buf 所在的那一页的位置
+	consumed int    // buf 所在的那一页已经返回的元素个数
+	started  bool
+	fetched  bool            // 是否已经请求过至少一页
+	pending  []pageResult[T] // 已经请求、还没有开始返回的页
+	more     bool            // 是否还有下一页
+	buf      []T             // 已经请求、还没有返回的元素
+	cur      T
+	err      error
+}
+
+// pageResult 是一次请求的结果
+type pageResult[T any] struct {
+	cursor Cursor
+	items  []T
+	more   bool
+	err    error
+}
+
+// UserIterator 逐个返回用户，如粉丝、关注者
+type UserIterator = Iterator[*User]
+
+// QuestionIterator 逐个返回问题
+type QuestionIterator = Iterator[*Question]
+
+// AnswerIterator 逐个返回回答
+type AnswerIterator = Iterator[*Answer]
+
+// CollectionIterator 逐个返回收藏夹
+type CollectionIterator = Iterator[*Collection]
+
+// TopicIterator 逐个返回话题
+type TopicIterator = Iterator[*Topic]
+
+// CommentIterator 逐个返回评论
+type CommentIterator = Iterator[*Comment]
+
+// ColumnIterator 逐个返回专栏
+type ColumnIterator = Iterator[*Column]
+
+// ArticleIterator 逐个返回专栏文章
+type ArticleIterator = Iterator[*Article]
+
+// ActivityIterator 逐个返回用户动态
+type ActivityIterator = Iterator[*Activity]
+
+// QuestionLogIterator 逐个返回问题日志
+type QuestionLogIterator = Iterator[*QuestionLog]
+
+// newIterator 创建一个 Iterator，kind 和 link 用于标识列表，记录在 Cursor 里
+func newIterator[T any](ctx context.Context, kind, link string, fetch pageFetcher[T]) *Iterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Iterator[T]{
+		ctx:   ctx,
+		fetch: fetch,
+		next:  Cursor{Kind: kind, URL: link},
+		more:  true,
+	}
+}
+
+// concurrent 设置同时请求的页数，workers <= 1 时逐页请求。只能用于按 Cursor.Page 翻页的列表，
+// 因为同时请求时后面几页的 Offset 还不知道
+func (it *Iterator[T]) concurrent(workers int) *Iterator[T] {
+	it.workers = workers
+	return it
+}
+
+// emptyIterator 返回一个没有任何元素的 Iterator，如匿名用户的粉丝
+func emptyIterator[T any](kind, link string) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:  context.Background(),
+		next: Cursor{Kind: kind, URL: link, Done: true},
+	}
+}
+
+// Next 前进到下一个元素，没有更多元素或出错时返回 false，之后可以用 Err 检查是否出错
+func (it *Iterator[T]) Next() bool {
+	it.started = true
+	for len(it.buf) == 0 {
+		if !it.more || it.err != nil {
+			return false
+		}
+		if len(it.pending) == 0 {
+			if err := it.ctx.Err(); err != nil {
+				it.err = err
+				return false
+			}
+			it.pending = it.fetchPages()
+		}
+
+		result := it.pending[0]
+		it.pending = it.pending[1:]
+		if result.err != nil {
+			it.err, it.pending = result.err, nil
+			return false
+		}
+
+		cursor := result.cursor
+		cursor.Offset, cursor.Start = it.next.Offset, it.next.Start
+		it.current = cursor
+		it.next = cursor
+		it.next.Page++
+		it.next.Offset += len(result.items)
+		it.next.Start = result.cursor.Start
+		it.next.Skip = 0
+		it.more = result.more
+		if !result.more {
+			// 同时请求时，最后一页之后的结果都是多余的
+			it.pending = nil
+		}
+
+		// 从 Cursor 继续时，跳过这一页已经返回过的元素
+		skip := minInt(cursor.Skip, len(result.items))
+		it.buf, it.consumed = result.items[skip:], skip
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	it.consumed++
+	return true
+}
+
+// fetchPages 从 it.next 开始请求下一页，设置了 workers 时同时请求后面的 workers 页，结果按页码排列。
+// 第一页总是单独请求，这样问题、收藏夹等页面在同时请求之前就已经载入
+func (it *Iterator[T]) fetchPages() []pageResult[T] {
+	n := 1
+	if it.workers > 1 && it.fetched {
+		n = it.workers
+	}
+	it.fetched = true
+
+	results := make([]pageResult[T], n)
+	fetch := func(i int) {
+		cursor := it.next
+		if i > 0 {
+			cursor.Page += i
+			cursor.Skip = 0
+		}
+		items, more, err := it.fetch(it.ctx, &cursor)
+		results[i] = pageResult[T]{cursor: cursor, items: items, more: more, err: err}
+	}
+
+	if n == 1 {
+		fetch(0)
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fetch(i)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// Value 返回当前的元素，需要在 Next 返回 true 之后调用
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err 返回遍历过程中遇到的错误，正常结束时返回 nil
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All 返回一个可以用 for range 遍历的序列，遍历结束后需要用 Err 检查是否出错
+func (it *Iterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Cursor 返回当前的位置，从这个位置继续时，第一个元素是 Value 之后的那一个
+func (it *Iterator[T]) Cursor() Cursor {
+	if len(it.buf) > 0 {
+		cursor := it.current
+		cursor.Skip = it.consumed
+		return cursor
+	}
+	cursor := it.next
+	cursor.Done = !it.more
+	return cursor
+}
+
+// Seek 从 cursor 的位置继续遍历，需要在第一次调用 Next 之前调用。
+// cursor 需要来自同一个列表（Kind 和 URL 都相同），否则返回错误
+func (it *Iterator[T]) Seek(cursor Cursor) error {
+	if it.started {
+		return fmt.Errorf("zhihu: 已经开始遍历，不能再调用 Seek")
+	}
+	if cursor.Kind != it.next.Kind || cursor.URL != it.next.URL {
+		return fmt.Errorf("zhihu: Cursor 属于 %s %s，不是 %s %s", cursor.Kind, cursor.URL, it.next.Kind, it.next.URL)
+	}
+
+	it.next = cursor
+	it.next.Done = false
+	it.more = !cursor.Done
+	return nil
+}
+
+// take 返回前 n 个元素，n < 0 时返回所有元素；出错时返回已经获取的元素和错误
+func (it *Iterator[T]) take(n int) ([]T, error) {
+	var items []T
+	for (n < 0 || len(items) < n) && it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,194 @@
+package zhihu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFetcher 返回共 pages 页、每页 size 个数字的 pageFetcher，并记录请求了多少次
+func countingFetcher(pages, size int, calls *int) pageFetcher[int] {
+	return func(ctx context.Context, cursor *Cursor) ([]int, bool, error) {
+		*calls++
+		items := make([]int, 0, size)
+		for i := 0; i < size; i++ {
+			items = append(items, cursor.Page*size+i)
+		}
+		return items, cursor.Page+1 < pages, nil
+	}
+}
+
+func Test_IteratorLazy(t *testing.T) {
+	calls := 0
+	it := newIterator(context.Background(), "test", "", countingFetcher(3, 20, &calls))
+
+	for i := 0; i < 25; i++ {
+		if !it.Next() {
+			t.Fatalf("Next() = false at %d, err = %v", i, it.Err())
+		}
+		if it.Value() != i {
+			t.Errorf("Value() = %d, expected %d", it.Value(), i)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("fetched %d pages after 25 items, expected 2", calls)
+	}
+
+	var rest []int
+	for v := range it.All() {
+		rest = append(rest, v)
+	}
+	if len(rest) != 35 || rest[0] != 25 || it.Err() != nil {
+		t.Errorf("rest = %d items starting at %v, err = %v", len(rest), rest[:1], it.Err())
+	}
+	if calls != 3 {
+		t.Errorf("fetched %d pages in total, expected 3", calls)
+	}
+}
+
+func Test_IteratorTake(t *testing.T) {
+	calls := 0
+	items, err := newIterator(context.Background(), "test", "", countingFetcher(5, 20, &calls)).take(3)
+	if err != nil || len(items) != 3 || calls != 1 {
+		t.Errorf("take(3) = %v, %v with %d fetches", items, err, calls)
+	}
+
+	calls = 0
+	items, _ = newIterator(context.Background(), "test", "", countingFetcher(5, 20, &calls)).take(-1)
+	if len(items) != 100 || calls != 5 {
+		t.Errorf("take(-1) returned %d items with %d fetches", len(items), calls)
+	}
+}
+
+func Test_IteratorError(t *testing.T) {
+	errBoom := errors.New("boom")
+	it := newIterator(context.Background(), "test", "", func(ctx context.Context, cursor *Cursor) ([]int, bool, error) {
+		if cursor.Page == 1 {
+			return nil, false, errBoom
+		}
+		return []int{1, 2}, true, nil
+	})
+
+	items, err := it.take(-1)
+	if len(items) != 2 || !errors.Is(err, errBoom) {
+		t.Errorf("take(-1) = %v, %v", items, err)
+	}
+	if it.Next() {
+		t.Errorf("Next() should stay false after an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	it = newIterator(ctx, "test", "", countingFetcher(2, 20, &calls))
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) || calls != 0 {
+		t.Errorf("canceled ctx: err = %v, calls = %d", it.Err(), calls)
+	}
+}
+
+func Test_IteratorCursor(t *testing.T) {
+	calls := 0
+	it := newIterator(context.Background(), "test", "https://www.zhihu.com/people/jixin", countingFetcher(3, 20, &calls))
+	for i := 0; i < 25; i++ {
+		it.Next()
+	}
+
+	data, err := json.Marshal(it.Cursor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Page != 1 || cursor.Offset != 20 || cursor.Skip != 5 || cursor.Done {
+		t.Errorf("unexpected cursor: %s", data)
+	}
+
+	calls = 0
+	resumed := newIterator(context.Background(), "test", "https://www.zhihu.com/people/jixin", countingFetcher(3, 20, &calls))
+	if err := resumed.Seek(cursor); err != nil {
+		t.Fatal(err)
+	}
+	items, err := resumed.take(-1)
+	if err != nil || len(items) != 35 || items[0] != 25 || calls != 2 {
+		t.Errorf("resumed: %d items starting at %v, err = %v, calls = %d", len(items), items[:1], err, calls)
+	}
+	if end := resumed.Cursor(); !end.Done || end.Page != 3 || end.Offset != 60 {
+		t.Errorf("unexpected end cursor: %+v", end)
+	}
+
+	other := newIterator(context.Background(), "test", "https://www.zhihu.com/people/other", countingFetcher(3, 20, &calls))
+	if err := other.Seek(cursor); err == nil {
+		t.Errorf("Seek() with a cursor of another list should fail")
+	}
+
+	done := newIterator(context.Background(), "test", "https://www.zhihu.com/people/jixin", countingFetcher(3, 20, &calls))
+	calls = 0
+	if done.Seek(resumed.Cursor()); done.Next() || calls != 0 {
+		t.Errorf("Seek() to a finished cursor: Next() should be false without fetching, calls = %d", calls)
+	}
+}
+
+func Test_IteratorConcurrent(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		running, maxRun int
+		pages           []int
+	)
+	fetch := func(ctx context.Context, cursor *Cursor) ([]int, bool, error) {
+		mu.Lock()
+		running++
+		if running > maxRun {
+			maxRun = running
+		}
+		pages = append(pages, cursor.Page)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if cursor.Page >= 10 {
+			return nil, false, nil
+		}
+		return []int{cursor.Page * 2, cursor.Page*2 + 1}, cursor.Page+1 < 10, nil
+	}
+
+	it := newIterator(context.Background(), "test", "", fetch).concurrent(4)
+	items, err := it.take(-1)
+	if err != nil || len(items) != 20 {
+		t.Fatalf("take(-1) = %v, %v", items, err)
+	}
+	for i, v := range items {
+		if v != i {
+			t.Fatalf("items out of order: %v", items)
+		}
+	}
+	if maxRun != 4 {
+		t.Errorf("at most %d pages were fetched at the same time, expected 4", maxRun)
+	}
+	// 第一页单独请求，之后每次 4 页：0, 1-4, 5-8, 9-12
+	if len(pages) != 13 {
+		t.Errorf("fetched pages %v", pages)
+	}
+	if end := it.Cursor(); !end.Done || end.Page != 10 || end.Offset != 20 {
+		t.Errorf("unexpected end cursor: %+v", end)
+	}
+
+	errBoom := errors.New("boom")
+	it = newIterator(context.Background(), "test", "", func(ctx context.Context, cursor *Cursor) ([]int, bool, error) {
+		if cursor.Page == 2 {
+			return nil, false, errBoom
+		}
+		return []int{cursor.Page}, true, nil
+	}).concurrent(4)
+	items, err = it.take(-1)
+	if len(items) != 2 || !errors.Is(err, errBoom) {
+		t.Errorf("take(-1) = %v, %v, expected the pages before the error", items, err)
+	}
+}
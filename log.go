@@ -1,49 +1,234 @@
 package zhihu
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
 
 	"github.com/fatih/color"
 )
 
-// Logger 是一个简单的输出工具，可以输出不同颜色的信息
-// TODO simple level
-type Logger struct {
-	Enabled bool
+// Level 是日志的级别
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String 返回级别的名称，如 INFO
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(level))
 }
 
-func (logger *Logger) log(a ...interface{}) {
-	if logger.Enabled {
-		fmt.Println(a...)
+// Logger 是 zhihu-go 输出日志的接口。msg 是一句简短的描述，keyvals 是成对出现的键和值，
+// 如 logger.Info("GET", "url", link)，同 log/slog 的约定，因此 *slog.Logger 可以直接作为 Logger 使用
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// SetLogger 替换默认的 Logger，之后创建的 Session 和默认 Client 都会使用它。l 为 nil 时不输出任何日志
+func SetLogger(l Logger) {
+	if l == nil {
+		l = DiscardLogger
 	}
+	logger = l
+	defaultClient.SetLogger(l)
 }
 
-// Error 输出 error 级别的日志
-func (logger *Logger) Error(msg string, a ...interface{}) {
-	logger.log(color.RedString("ERROR: "+msg, a...))
+// TextLogger 把日志以文本的形式输出到 io.Writer，每条一行，如：
+//
+//	INFO: GET url=https://www.zhihu.com/question/28966220
+//
+// 这是默认的 Logger，输出到标准输出，不同级别使用不同的颜色
+type TextLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level Level
+	color bool
 }
 
-// Warn 输出 warning 级别的日志
-func (logger *Logger) Warn(msg string, a ...interface{}) {
-	logger.log(color.YellowString("WARN: "+msg, a...))
+// NewTextLogger 创建一个 TextLogger，w 为 nil 时输出到标准输出。默认输出所有级别的日志，并且使用颜色
+func NewTextLogger(w io.Writer) *TextLogger {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &TextLogger{w: w, level: LevelDebug, color: true}
 }
 
-// Warning 是 Warn 的别名
-func (logger *Logger) Warning(msg string, a ...interface{}) {
-	logger.Warn(msg, a...)
+// SetLevel 设置最低的级别，低于 level 的日志不会输出
+func (l *TextLogger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
 }
 
-// Info 输出 info 级别的日志
-func (logger *Logger) Info(msg string, a ...interface{}) {
-	logger.log(color.BlueString("INFO: "+msg, a...))
+// SetColor 设置是否使用颜色，输出到文件时应该关闭
+func (l *TextLogger) SetColor(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.color = enabled
 }
 
 // Debug 输出 debug 级别的日志
-func (logger *Logger) Debug(msg string, a ...interface{}) {
-	logger.log(color.WhiteString("DEBUG: "+msg, a...))
+func (l *TextLogger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
 }
 
-// Success 输出 success 的日志，基本上与 info 一样，除了使用了绿色
-func (logger *Logger) Success(msg string, a ...interface{}) {
-	logger.log(color.GreenString("SUCCESS: "+msg, a...))
+// Info 输出 info 级别的日志
+func (l *TextLogger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+// Warn 输出 warning 级别的日志
+func (l *TextLogger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+// Error 输出 error 级别的日志
+func (l *TextLogger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *TextLogger) log(level Level, msg string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level {
+		return
+	}
+
+	line := formatLogLine(level, msg, keyvals)
+	if l.color {
+		c := levelColors[level]
+		if c == nil {
+			c = levelColors[LevelInfo]
+		}
+		line = c.Sprint(line)
+	}
+	fmt.Fprintln(l.w, line)
+}
+
+var levelColors = map[Level]*color.Color{
+	LevelDebug: color.New(color.FgWhite),
+	LevelInfo:  color.New(color.FgBlue),
+	LevelWarn:  color.New(color.FgYellow),
+	LevelError: color.New(color.FgRed),
+}
+
+// formatLogLine 把一条日志格式化成 "LEVEL: msg key=value ..."，值里有空白时加上引号
+func formatLogLine(level Level, msg string, keyvals []interface{}) string {
+	var buf bytes.Buffer
+	buf.WriteString(level.String())
+	buf.WriteString(": ")
+	buf.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		buf.WriteByte(' ')
+		if i+1 == len(keyvals) {
+			// 落单的值，同 slog 一样用 !BADKEY 作为键
+			fmt.Fprintf(&buf, "!BADKEY=%s", formatLogValue(keyvals[i]))
+			break
+		}
+		fmt.Fprintf(&buf, "%v=%s", keyvals[i], formatLogValue(keyvals[i+1]))
+	}
+	return buf.String()
+}
+
+func formatLogValue(value interface{}) string {
+	text := fmt.Sprint(value)
+	if text == "" || bytes.ContainsAny([]byte(text), " \t\r\n\"=") {
+		return fmt.Sprintf("%q", text)
+	}
+	return text
+}
+
+// NewSlogLogger 返回一个把日志交给 handler 处理的 Logger，用于把 zhihu-go 的日志接入服务自己的结构化日志，
+// 最低级别等由 handler 决定。已经有 *slog.Logger 时可以直接使用，不需要转换
+func NewSlogLogger(handler slog.Handler) Logger {
+	return slog.New(handler)
+}
+
+// DiscardLogger 丢弃所有的日志
+var DiscardLogger Logger = discardLogger{}
+
+type discardLogger struct{}
+
+func (discardLogger) Debug(string, ...interface{}) {}
+func (discardLogger) Info(string, ...interface{})  {}
+func (discardLogger) Warn(string, ...interface{})  {}
+func (discardLogger) Error(string, ...interface{}) {}
+
+// SlogHandler 返回一个 slog.Handler，把 slog 的日志转交给 l 输出，可以让使用 slog 的代码和 zhihu-go 共用一个 TextLogger
+func SlogHandler(l Logger) slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+type slogHandler struct {
+	logger Logger
+	attrs  []interface{}
+	group  string
+}
+
+func (h *slogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	keyvals := append([]interface{}{}, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		keyvals = append(keyvals, h.key(attr.Key), attr.Value.String())
+		return true
+	})
+
+	switch {
+	case record.Level >= slog.LevelError:
+		h.logger.Error(record.Message, keyvals...)
+	case record.Level >= slog.LevelWarn:
+		h.logger.Warn(record.Message, keyvals...)
+	case record.Level >= slog.LevelInfo:
+		h.logger.Info(record.Message, keyvals...)
+	default:
+		h.logger.Debug(record.Message, keyvals...)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]interface{}{}, h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, h.key(attr.Key), attr.Value.String())
+	}
+	return &clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.group = h.key(name)
+	return &clone
+}
+
+func (h *slogHandler) key(name string) string {
+	if h.group == "" {
+		return name
+	}
+	return h.group + "." + name
 }
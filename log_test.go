@@ -1,17 +1,60 @@
 package zhihu
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 )
 
-func Test_Error(t *testing.T) {
-	var logger = Logger{Enabled: true}
-	logger.Error("测试：输出一条 ERROR 的信息")
-	logger.Error("测试：从 1 到 5 分别是：%d, %d, %d, %d, %d", 1, 2, 3, 4, 5)
+func Test_TextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewTextLogger(&buf)
+	logger.SetColor(false)
+	logger.SetLevel(LevelInfo)
+
+	logger.Debug("不会输出")
+	logger.Info("GET", "url", "https://www.zhihu.com/question/28966220")
+	logger.Error("请求失败", "err", errors.New("connection reset"), "attempt", 2, "dangling")
+
+	expected := "INFO: GET url=https://www.zhihu.com/question/28966220\n" +
+		`ERROR: 请求失败 err="connection reset" attempt=2 !BADKEY=dangling` + "\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func Test_NewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	logger.Info("不会输出")
+	logger.Warn("请求被限流", "url", "https://www.zhihu.com/people/jixin", "attempt", 1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "请求被限流" || record["attempt"] != 1.0 {
+		t.Errorf("unexpected record: %v", record)
+	}
 }
 
-func Test_Info(t *testing.T) {
-	var logger = Logger{Enabled: true}
-	logger.Info("测试：输出一条 INFO 的信息")
-	logger.Info("测试：从 1 到 5 分别是：%d, %d, %d, %d, %d", 1, 2, 3, 4, 5)
+func Test_SlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewTextLogger(&buf)
+	logger.SetColor(false)
+
+	slog.New(SlogHandler(logger)).WithGroup("req").With("method", "GET").Warn("重试", "attempt", 2)
+
+	expected := "WARN: 重试 req.method=GET req.attempt=2\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
 }
@@ -0,0 +1,369 @@
+package zhihu
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var (
+	reBlankLines  = regexp.MustCompile(`\n{3,}`)
+	reWhitespaces = regexp.MustCompile(`\s+`)
+	reListBlank   = regexp.MustCompile(`\n\s*\n`)
+	reBlockMarker = regexp.MustCompile(`^\s*(?:[#>+=-]|[0-9]+[.)])`)
+	reBackticks   = regexp.MustCompile("`+")
+)
+
+// altEscaper 转义图片描述里的方括号，避免提前结束 ![...] 的语法
+var altEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// markdownEscaper 转义文本里会被当成 Markdown 标记的字符
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`,
+)
+
+// htmlToMarkdown 把（经过 answerSelectionToHtml 处理的）HTML 片段转换成 Markdown
+func htmlToMarkdown(sel *goquery.Selection) string {
+	var buf strings.Builder
+	for _, node := range sel.Nodes {
+		buf.WriteString(convertNode(node))
+	}
+	md := reBlankLines.ReplaceAllString(cleanLines(buf.String()), "\n\n")
+	return strings.TrimSpace(md) + "\n"
+}
+
+// cleanLines 清理 HTML 标签之间的空白文本在行首留下的单个空格，代码块里的内容保持不变。
+// 嵌套的列表至少缩进两个空格，不受影响
+func cleanLines(md string) string {
+	lines := strings.Split(md, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		} else if strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "  ") {
+			lines[i] = line[1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownFrontMatter 生成 Markdown 文件头部的元信息
+func markdownFrontMatter(title, author string, upvote int, link string) string {
+	lines := []string{
+		"---",
+		fmt.Sprintf("title: %q", title),
+		fmt.Sprintf("author: %q", author),
+		fmt.Sprintf("upvote: %d", upvote),
+		fmt.Sprintf("link: %s", link),
+		"---",
+	}
+	return strings.Join(lines, "\n") + "\n\n"
+}
+
+func convertNode(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return escapeMarkdown(reWhitespaces.ReplaceAllString(node.Data, " "))
+	case html.ElementNode:
+		// 下面处理
+	case html.DocumentNode:
+		return convertChildren(node)
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "head", "script", "style", "noscript":
+		return ""
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(node.Data[1] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " " + strings.TrimSpace(convertChildren(node)) + "\n\n"
+	case "p", "div", "section", "figure":
+		return "\n\n" + strings.TrimSpace(convertChildren(node)) + "\n\n"
+	case "br":
+		return "  \n"
+	case "hr":
+		return "\n\n---\n\n"
+	case "b", "strong":
+		return wrapInline(convertChildren(node), "**")
+	case "i", "em":
+		return wrapInline(convertChildren(node), "*")
+	case "del", "s", "strike":
+		return wrapInline(convertChildren(node), "~~")
+	case "code":
+		return convertCode(nodeText(node))
+	case "pre":
+		return convertPre(node)
+	case "blockquote":
+		return convertBlockquote(node)
+	case "ul", "ol":
+		return convertList(node)
+	case "table":
+		return convertTable(node)
+	case "img":
+		return convertImage(node)
+	case "a":
+		return convertLink(node)
+	}
+	return convertChildren(node)
+}
+
+func convertChildren(node *html.Node) string {
+	var buf strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		buf.WriteString(convertNode(child))
+	}
+	return buf.String()
+}
+
+// wrapInline 给行内元素加上标记，标记需要紧贴文字，所以把首尾空白移到标记外面
+func wrapInline(text, mark string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	var prefix, suffix string
+	if strings.HasPrefix(text, " ") {
+		prefix = " "
+	}
+	if strings.HasSuffix(text, " ") {
+		suffix = " "
+	}
+	return prefix + mark + trimmed + mark + suffix
+}
+
+// escapeMarkdown 转义文本节点里的 Markdown 标记。文本节点可能位于行首，
+// 所以开头的 #、>、-、+、= 和“1.”这样的序号也要转义，在行中间时多出的反斜杠不影响显示
+func escapeMarkdown(text string) string {
+	text = markdownEscaper.Replace(text)
+	if loc := reBlockMarker.FindStringIndex(text); loc != nil {
+		// 只转义最后一个字符，序号转义后面的点或括号，如 1\.
+		at := loc[1] - 1
+		return text[:at] + `\` + text[at:]
+	}
+	return text
+}
+
+// convertCode 处理行内代码，代码里有反引号时用更长的反引号包起来，
+// 首尾是反引号时再加上空格，避免和外面的反引号连在一起
+func convertCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	longest := 0
+	for _, run := range reBackticks.FindAllString(code, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// convertPre 处理代码块，知乎的代码块形如：
+// <div class="highlight"><pre><code class="language-python">...</code></pre></div>
+func convertPre(node *html.Node) string {
+	lang := getAttr(node, "lang")
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "code" {
+			for _, class := range strings.Fields(getAttr(child, "class")) {
+				if strings.HasPrefix(class, "language-") {
+					lang = strings.TrimPrefix(class, "language-")
+				}
+			}
+		}
+	}
+	code := strings.TrimRight(nodeText(node), "\n")
+	return "\n\n```" + lang + "\n" + code + "\n```\n\n"
+}
+
+func convertBlockquote(node *html.Node) string {
+	content := strings.TrimSpace(reBlankLines.ReplaceAllString(cleanLines(convertChildren(node)), "\n\n"))
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+func convertList(node *html.Node) string {
+	var (
+		items   []string
+		ordered = node.Data == "ol"
+		index   = 1
+	)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+
+		// 列表项里的段落和嵌套列表都合并成紧凑的多行，续行缩进到标记之后
+		content := strings.TrimSpace(cleanLines(convertChildren(child)))
+		content = reListBlank.ReplaceAllString(content, "\n")
+		lines := strings.Split(content, "\n")
+		indent := strings.Repeat(" ", len(marker))
+		for i := range lines {
+			if i == 0 {
+				lines[i] = marker + lines[i]
+			} else {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, strings.Join(lines, "\n"))
+	}
+	return "\n\n" + strings.Join(items, "\n") + "\n\n"
+}
+
+// convertTable 转换成 GFM 表格，第一行作为表头
+func convertTable(node *html.Node) string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.Data != "tr" {
+				walk(child)
+				continue
+			}
+			var cells []string
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+					text := strings.TrimSpace(convertChildren(cell))
+					text = strings.Replace(reWhitespaces.ReplaceAllString(text, " "), "|", `\|`, -1)
+					cells = append(cells, text)
+				}
+			}
+			rows = append(rows, cells)
+		}
+	}
+	walk(node)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+// convertImage 处理图片，知乎的公式是以图片的形式展示的：
+// <img src="https://www.zhihu.com/equation?tex=E%3Dmc%5E2" alt="E=mc^2" class="ee_img tex">
+func convertImage(node *html.Node) string {
+	src := getAttr(node, "src")
+	alt := getAttr(node, "alt")
+	if tex, ok := texFromImage(src, alt); ok {
+		return "$" + tex + "$"
+	}
+	if src == "" {
+		return ""
+	}
+	return "![" + altEscaper.Replace(alt) + "](" + src + ")"
+}
+
+func texFromImage(src, alt string) (string, bool) {
+	if !strings.Contains(src, "/equation?") {
+		return "", false
+	}
+	if alt != "" {
+		return alt, true
+	}
+	link, err := url.Parse(src)
+	if err != nil {
+		return "", false
+	}
+	tex := link.Query().Get("tex")
+	return tex, tex != ""
+}
+
+func convertLink(node *html.Node) string {
+	text := strings.TrimSpace(convertChildren(node))
+	href := unwrapZhihuLink(getAttr(node, "href"))
+	if href == "" || strings.HasPrefix(href, "javascript:") {
+		return text
+	}
+	if text == "" {
+		text = href
+	}
+	return "[" + text + "](" + href + ")"
+}
+
+// unwrapZhihuLink 还原知乎的外链，知乎的外链都是这种形式：https://link.zhihu.com/?target=xxx，
+// 其他 host 的链接即使带有 target 参数也保持不变
+func unwrapZhihuLink(href string) string {
+	if !strings.Contains(href, "target=") {
+		return href
+	}
+	link, err := url.Parse(href)
+	if err != nil || !strings.EqualFold(link.Hostname(), redirectHost) {
+		return href
+	}
+	if target := link.Query().Get("target"); target != "" {
+		return target
+	}
+	return href
+}
+
+func getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// nodeText 返回节点里的原始文本，不做空白处理，用于代码
+func nodeText(node *html.Node) string {
+	var buf strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && n.Data == "br" {
+			buf.WriteString("\n")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	return buf.String()
+}
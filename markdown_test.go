@@ -0,0 +1,89 @@
+package zhihu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func Test_htmlToMarkdown(t *testing.T) {
+	raw := `<div>
+<h2>标题</h2>
+<p>一段 <b>加粗</b> 和 <i>斜体</i> 的文字，<a href="https://link.zhihu.com/?target=https%3A//golang.org">Go</a></p>
+<ul><li>第一项</li><li>第二项<ol><li>子项</li></ol></li></ul>
+<blockquote>引用<br>第二行</blockquote>
+<div class="highlight"><pre><code class="language-go">func main() {
+	fmt.Println("hi")
+}</code></pre></div>
+<table><tr><th>a</th><th>b</th></tr><tr><td>1</td><td>2</td></tr></table>
+<img src="https://pic1.zhimg.com/abc_b.jpg"><br>
+<img src="https://www.zhihu.com/equation?tex=E%3Dmc%5E2" alt="E=mc^2" class="ee_img tex">
+</div>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := htmlToMarkdown(doc.Find("body"))
+	want := "## 标题\n\n" +
+		"一段 **加粗** 和 *斜体* 的文字，[Go](https://golang.org)\n\n" +
+		"- 第一项\n- 第二项\n  1. 子项\n\n" +
+		"> 引用\n> 第二行\n\n" +
+		"```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n" +
+		"| a | b |\n| --- | --- |\n| 1 | 2 |\n\n" +
+		"![](https://pic1.zhimg.com/abc_b.jpg)  \n$E=mc^2$\n"
+	if got != want {
+		t.Errorf("htmlToMarkdown returns error result:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_htmlToMarkdownEscape(t *testing.T) {
+	ioMap := map[string]string{
+		`<p>2*3*4 = 24，snake_case_name</p>`:                               `2\*3\*4 = 24，snake\_case\_name`,
+		`<p># 不是标题</p>`:                                                   `\# 不是标题`,
+		`<p>1. 不是列表</p>`:                                                  `1\. 不是列表`,
+		`<p>- 也不是列表</p>`:                                                  `\- 也不是列表`,
+		`<p>[不是链接](x) &lt;br&gt; a\b</p>`:                                 `\[不是链接\](x) \<br> a\\b`,
+		`<p>第一行<br>&gt; 不是引用</p>`:                                         "第一行  \n\\> 不是引用",
+		`<p><img src="https://pic1.zhimg.com/abc_b.jpg" alt="图 [1]"></p>`: `![图 \[1\]](https://pic1.zhimg.com/abc_b.jpg)`,
+		`<p><a href="https://example.com/search?target=foo">搜索</a></p>`:   `[搜索](https://example.com/search?target=foo)`,
+		`<p><code>a*b_c</code></p>`:                                       "`a*b_c`",
+		`<p><code>x := ` + "`" + `raw` + "`" + `</code></p>`:              "`` x := `raw` ``",
+		`<p><code>a ` + "``" + ` b</code></p>`:                            "```a `` b```",
+	}
+	for raw, want := range ioMap {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := htmlToMarkdown(doc.Find("body")); got != want+"\n" {
+			t.Errorf("htmlToMarkdown(%q) = %q, want %q", raw, got, want+"\n")
+		}
+	}
+}
+
+func Test_answerContentToMarkdown(t *testing.T) {
+	raw := `<div class="zm-editable-content">质能方程 <img src="https://www.zhihu.com/equation?tex=E%3Dmc%5E2" alt="E=mc^2" eeimg="1"><br>` +
+		`<img src="//zhstatic.zhihu.com/assets/zhihu/ztext/whitedot.jpg" data-actualsrc="https://pic1.zhimg.com/abc_b.jpg" class="content_image">` +
+		`<br>结束</div>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 和 Answer.ToMarkdown 一样，先经过 answerSelectionToHtml 再转换
+	content, err := answerSelectionToHtml(doc.Find("div.zm-editable-content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err = goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := htmlToMarkdown(doc.Find("body"))
+	want := "质能方程 $E=mc^2$  \n![](https://pic1.zhimg.com/abc_b.jpg)  \n结束\n"
+	if got != want {
+		t.Errorf("answer content to markdown returns %q, want %q", got, want)
+	}
+}
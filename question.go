@@ -1,12 +1,13 @@
 package zhihu
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -19,27 +20,29 @@ type Question struct {
 	title string
 }
 
-// NewQuestion 通过给定的 URL 创建一个 Question 对象
+// NewQuestion 通过给定的 URL 创建一个 Question 对象，链接不合法时会 panic。
+//
+// Deprecated: 请使用 Client.Question（如 DefaultClient().Question），链接不合法时返回 ErrInvalidURL 而不是 panic
 func NewQuestion(link string, title string) *Question {
-	if !validQuestionURL(link) {
-		panic("问题链接不正确: " + link)
+	question, err := defaultClient.Question(link, title)
+	if err != nil {
+		panic("问题链接不正确：" + link)
 	}
+	return question
+}
 
+func newQuestion(client *Client, link string, title string) *Question {
 	return &Question{
-		Page:  newZhihuPage(link),
+		Page:  newZhihuPage(client, link),
 		title: title,
 	}
 }
 
 // GetTitle 获取问题标题
 func (q *Question) GetTitle() string {
-	if q.title != "" {
-		return q.title
-	}
-
-	doc := q.Doc()
-	q.title = strip(doc.Find("h2.zm-item-title").First().Text())
-	return q.title
+	return lazyField(q.Page, &q.title, func() string {
+		return strip(q.Doc().Find("h2.zm-item-title").First().Text())
+	})
 }
 
 // GetDetail 获取问题描述
@@ -54,20 +57,29 @@ func (q *Question) GetDetail() string {
 	return detail
 }
 
-// GetAnswersNum 获取问题回答数量
+// GetAnswersNum 获取问题回答数量，出错时返回 0，需要区分“没有回答”和“请求失败”时请使用 GetAnswersNumCtx
 func (q *Question) GetAnswersNum() int {
+	num, _ := q.GetAnswersNumCtx(context.Background())
+	return num
+}
+
+// GetAnswersNumCtx 同 GetAnswersNum，ctx 取消或超时后请求会被中止；页面载入失败时返回错误
+func (q *Question) GetAnswersNumCtx(ctx context.Context) (int, error) {
 	if got, ok := q.getIntField("answers-num"); ok {
-		return got
+		return got, nil
 	}
 
-	doc := q.Doc()
+	doc, err := q.DocCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
 	data, exists := doc.Find("h3#zh-question-answer-num").Attr("data-num")
 	answerNum := 0
 	if exists {
 		answerNum, _ = strconv.Atoi(data)
 	}
 	q.setField("answers-num", answerNum)
-	return answerNum
+	return answerNum, nil
 }
 
 // GetFollowersNum 获取问题关注数量
@@ -89,7 +101,7 @@ func (q *Question) GetTopics() []*Topic {
 	q.Doc().Find("a.zm-item-tag").Each(func(index int, sel *goquery.Selection) {
 		name := strip(sel.Text())
 		href, _ := sel.Attr("href")
-		thisTopic := NewTopic(makeZhihuLink(href), name)
+		thisTopic := newTopic(q.client, q.client.makeZhihuLink(href), name)
 		topics = append(topics, thisTopic)
 	})
 	return topics
@@ -97,17 +109,20 @@ func (q *Question) GetTopics() []*Topic {
 
 // GetFollowersN 返回 n 个关注者，如果 n < 0，返回所有关注者
 func (q *Question) GetFollowersN(n int) []*User {
-	var (
-		link = urlJoin(q.Link, "/followers")
-		xsrf = q.GetXSRF()
-	)
-	users, err := ajaxGetFollowers(link, xsrf, n)
-	if err != nil {
-		return nil
-	}
+	users, _ := q.GetFollowersNCtx(context.Background(), n)
 	return users
 }
 
+// GetFollowersNCtx 同 GetFollowersN，ctx 取消或超时后停止翻页并返回错误
+func (q *Question) GetFollowersNCtx(ctx context.Context, n int) ([]*User, error) {
+	return q.FollowersIter(ctx).take(n)
+}
+
+// FollowersIter 返回一个逐个获取关注者的 Iterator
+func (q *Question) FollowersIter(ctx context.Context) *UserIterator {
+	return q.client.followersIter(ctx, "question.followers", q.Page, urlJoin(q.Link, "/followers"))
+}
+
 // GetFollowers 获取关注该问题的用户
 func (q *Question) GetFollowers() []*User {
 	return q.GetFollowersN(q.GetFollowersNum())
@@ -118,26 +133,51 @@ func (q *Question) GetAllAnswers() []*Answer {
 	return q.GetTopXAnswers(q.GetAnswersNum())
 }
 
+// GetAllAnswersCtx 同 GetAllAnswers，ctx 取消或超时后停止翻页并返回错误
+func (q *Question) GetAllAnswersCtx(ctx context.Context) ([]*Answer, error) {
+	return q.GetTopXAnswersCtx(ctx, -1)
+}
+
 // GetTopXAnswers 获取问题 Top X 的答案
 func (q *Question) GetTopXAnswers(x int) []*Answer {
-	if x < 0 || x > q.GetAnswersNum() {
-		x = q.GetAnswersNum()
-	}
+	answers, _ := q.GetTopXAnswersCtx(context.Background(), x)
+	return answers
+}
 
-	// 1. 首页的回答
-	answers := q.getAnswersOnIndex()
+// GetTopXAnswersCtx 同 GetTopXAnswers，ctx 取消或超时后停止翻页并返回错误
+func (q *Question) GetTopXAnswersCtx(ctx context.Context, x int) ([]*Answer, error) {
+	return q.AnswersIter(ctx).take(x)
+}
 
-	if x < len(answers) {
-		return answers[:x]
-	}
+// errNoMoreAnswers 表示请求的页超出了问题的回答数量
+var errNoMoreAnswers = errors.New("zhihu: 没有更多回答了")
 
-	// 2. "更多"，调用 Ajax 接口
-	moreCount := x - pageSize
-	if moreCount > 0 {
-		answers = append(answers, q.getMoreAnswers(moreCount)...)
-	}
+// AnswersIter 返回一个逐个获取回答的 Iterator，第一页是问题页面上的回答，之后每页调用一次 Ajax 接口。
+// 某一页加载失败时停止遍历，错误由 Err 返回
+func (q *Question) AnswersIter(ctx context.Context) *AnswerIterator {
+	return newIterator(ctx, "question.answers", q.Link, func(ctx context.Context, cursor *Cursor) ([]*Answer, bool, error) {
+		if _, err := q.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
 
-	return answers
+		page := cursor.Page
+		if page > 0 && page*pageSize >= q.GetAnswersNum() {
+			return nil, false, nil
+		}
+		more := (page+1)*pageSize < q.GetAnswersNum()
+		if page == 0 {
+			return q.getAnswersOnIndex(), more, nil
+		}
+
+		answers, err := q.getAnswersByAjax(ctx, page)
+		if errors.Is(err, errNoMoreAnswers) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return answers, more, nil
+	}).concurrent(q.client.session.concurrency)
 }
 
 // GetTopAnswer 获取问题排名第一的答案
@@ -162,6 +202,29 @@ func (q *Question) GetCommentsNum() int {
 	return rv
 }
 
+// GetComments 返回问题的所有评论
+func (q *Question) GetComments() []*Comment {
+	return q.GetCommentsN(-1)
+}
+
+// GetCommentsN 返回前 n 条评论，如果 n < 0，返回所有评论
+func (q *Question) GetCommentsN(n int) []*Comment {
+	comments, _ := q.GetCommentsNCtx(context.Background(), n)
+	return comments
+}
+
+// GetCommentsNCtx 同 GetCommentsN，ctx 取消或超时后停止翻页并返回错误
+func (q *Question) GetCommentsNCtx(ctx context.Context, n int) ([]*Comment, error) {
+	return q.CommentsIter(ctx).take(n)
+}
+
+// CommentsIter 返回一个逐个获取评论的 Iterator
+func (q *Question) CommentsIter(ctx context.Context) *CommentIterator {
+	return q.client.commentsIter(ctx, "question.comments", q.Page, "questions", func() int {
+		return idFromLink(q.Link)
+	})
+}
+
 // GetVisitTimes 获取问题的访问次数
 func (q *Question) GetVisitTimes() int {
 	if got, ok := q.getIntField("visit-times"); ok {
@@ -178,6 +241,36 @@ func (q *Question) GetVisitTimes() int {
 	return visitTimes
 }
 
+// GetRelatedQuestions 返回页面侧边栏里的相关问题
+func (q *Question) GetRelatedQuestions() []*Question {
+	var questions []*Question
+	q.Doc().Find("div#zh-question-related-questions a.question_link").Each(func(index int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		questions = append(questions, newQuestion(q.client, q.client.makeZhihuLink(href), strip(sel.Text())))
+	})
+	return questions
+}
+
+// IsClosed 判断问题是否已经被关闭，关闭的问题不能再添加回答。
+// 被合并的问题会跳转到合并后的问题，见 GetRedirectTarget
+func (q *Question) IsClosed() bool {
+	return q.Doc().Find("div#zh-question-closed-msg").Size() > 0
+}
+
+// GetRedirectTarget 返回问题被合并（重定向）后的问题，没有重定向时返回 nil。
+// 访问被合并的问题时知乎会跳转到目标问题，所以此时 GetTitle 等方法返回的都是目标问题的数据
+func (q *Question) GetRedirectTarget() *Question {
+	doc := q.Doc()
+	if doc.Url == nil {
+		return nil
+	}
+	m := reQuestionPrefix.FindStringSubmatch(doc.Url.Path)
+	if m == nil || m[1] == strconv.Itoa(idFromLink(q.Link)) {
+		return nil
+	}
+	return newQuestion(q.client, q.client.makeZhihuLink("/question/"+m[1]), q.GetTitle())
+}
+
 func (q *Question) String() string {
 	return fmt.Sprintf("<Question: %s - %s>", q.GetTitle(), q.Link)
 }
@@ -196,30 +289,23 @@ func (q *Question) getAnswersOnIndex() []*Answer {
 }
 
 // getAnswersByAjax 处理 “更多” 回答，调用 Ajax 接口
-func (q *Question) getAnswersByAjax(page int) ([]*Answer, error) {
+func (q *Question) getAnswersByAjax(ctx context.Context, page int) ([]*Answer, error) {
 	offset := page * pageSize
 	if offset > q.GetAnswersNum() {
-		return nil, errors.New("No more answers.")
+		return nil, errNoMoreAnswers
 	}
 
 	// 如果 URL 是 https://www.zhihu.com/question/23759686，则 urlToken 是 23759686
-	urlToken, _ := strconv.Atoi(q.Link[len(q.Link)-8 : len(q.Link)])
+	urlToken := idFromLink(q.Link)
 
 	form := url.Values{}
 	form.Set("_xsrf", q.GetXSRF())
 	form.Set("method", "next")
 	form.Set("params", fmt.Sprintf(`{"url_token":%d,"pagesize":%d,"offset":%d}`, urlToken, pageSize, offset))
 
-	link := makeZhihuLink("/node/QuestionAnswerListV2")
-	body := strings.NewReader(form.Encode())
-	resp, err := gSession.Ajax(link, body, q.Link)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
+	link := q.client.makeZhihuLink("/node/QuestionAnswerListV2")
 	result := nodeListResult{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err := q.client.ajaxJSON(ctx, link, form, q.Link, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -237,30 +323,12 @@ func (q *Question) getAnswersByAjax(page int) ([]*Answer, error) {
 	return answers, nil
 }
 
-// getMoreAnswers 执行多次“更多”
-func (q *Question) getMoreAnswers(limit int) []*Answer {
-	answers := make([]*Answer, 0, limit)
-	index := 0
-	totalPage := (limit + pageSize - 1) / pageSize
-	for index < totalPage {
-		page := index + 1
-		moreAnswers, err := q.getAnswersByAjax(page)
-		if err != nil {
-			logger.Error("加载第 %d 页回答失败，问题：%s，错误：%s", page, q.Link, err.Error())
-		} else {
-			answers = append(answers, moreAnswers...)
-		}
-		index++
-	}
-	return answers
-}
-
 // processSingleAnswer 处理一个回答的 HTML 片段，
 // 这段 HTML 可能来自问题页面，也可能来自 Ajax 接口
 func (q *Question) processSingleAnswer(sel *goquery.Selection) *Answer {
 	// 1. 获取链接
 	answerHref, _ := sel.Find("a.answer-date-link").Attr("href")
-	answerLink := makeZhihuLink(answerHref)
+	answerLink := q.client.makeZhihuLink(answerHref)
 
 	// 2. 获取作者
 	authorSel := sel.Find("div.zm-item-answer-author-info")
@@ -273,10 +341,10 @@ func (q *Question) processSingleAnswer(sel *goquery.Selection) *Answer {
 		x := authorSel.Find("a.author-link")
 		userID := strip(x.Text())
 		userHref, _ := x.Attr("href")
-		author = NewUser(makeZhihuLink(userHref), userID)
+		author = newUser(q.client, q.client.makeZhihuLink(userHref), userID)
 	}
 
-	answer := NewAnswer(answerLink, q, author)
+	answer := newAnswer(q.client, answerLink, q, author)
 
 	// 3. 获取赞同数
 	dataIsOwner, _ := sel.Attr("data-isowner")
@@ -293,6 +361,9 @@ func (q *Question) processSingleAnswer(sel *goquery.Selection) *Answer {
 	content, _ := answerSelectionToHtml(sel.Find("div.zm-editable-content"))
 	answer.setContent(content)
 
+	// 5. 获取发布、编辑时间
+	answer.setTimes(sel, time.Now())
+
 	return answer
 }
 
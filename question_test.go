@@ -1,33 +1,185 @@
 package zhihu
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
 
-func init_session() {
-	Init("./examples/config.json")
+func newReplayQuestion(t *testing.T) *Question {
+	question, err := newReplayClient().Question("https://www.zhihu.com/question/41171543", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := question.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+	return question
 }
 
 func Test_GetTitle(t *testing.T) {
-	init_session()
-
-	question := NewQuestion("https://www.zhihu.com/question/41171543", "")
+	question := newReplayQuestion(t)
 	got := question.GetTitle()
 	want := "如何评价第一局比赛 AlphaGo 战胜李世石？"
-	logger.Info("got title: %s", got)
-	logger.Info("expected title: %s", want)
 	if got != want {
-		t.Error("GetTitle() returns error result")
+		t.Errorf("GetTitle() returns %s, want %s", got, want)
 	}
 }
 
 func Test_GetDetail(t *testing.T) {
-	init_session()
-
-	question := NewQuestion("https://www.zhihu.com/question/41171543", "")
+	question := newReplayQuestion(t)
 	got := question.GetDetail()
 	want := "本题已收录至知乎圆桌 » 对弈人工智能，更多关于李世石对战人工智能的解读欢迎关注讨论。"
-	logger.Info("got detail: %s", got)
-	logger.Info("expected detail: %s", want)
 	if got != want {
-		t.Error("GetDetail() returns error result")
+		t.Errorf("GetDetail() returns %s, want %s", got, want)
+	}
+}
+
+func Test_QuestionNums(t *testing.T) {
+	question := newReplayQuestion(t)
+	ioMap := map[string][2]int{
+		"GetAnswersNum":   {question.GetAnswersNum(), 22},
+		"GetFollowersNum": {question.GetFollowersNum(), 15630},
+		"GetCommentsNum":  {question.GetCommentsNum(), 67},
+		"GetVisitTimes":   {question.GetVisitTimes(), 3217754},
+	}
+
+	for method, value := range ioMap {
+		if value[0] != value[1] {
+			t.Errorf("%s() returns %d, want %d", method, value[0], value[1])
+		}
+	}
+}
+
+func Test_QuestionGetTopics(t *testing.T) {
+	topics := newReplayQuestion(t).GetTopics()
+	if len(topics) != 3 {
+		t.Fatalf("GetTopics() returns %d topics, want 3", len(topics))
+	}
+	if topics[1].GetName() != "AlphaGo" || topics[1].Link != "https://www.zhihu.com/topic/19570667" {
+		t.Errorf("GetTopics() returns error result: %s", topics[1])
+	}
+}
+
+func Test_QuestionGetAllAnswers(t *testing.T) {
+	answers := newReplayQuestion(t).GetAllAnswers()
+
+	// 首页 2 个，Ajax 加载的第 2 页 2 个
+	if len(answers) != 4 {
+		t.Fatalf("GetAllAnswers() returns %d answers, want 4", len(answers))
+	}
+
+	first := answers[0]
+	if first.Link != "https://www.zhihu.com/question/41171543/answer/88475539" {
+		t.Errorf("answer link is %s", first.Link)
+	}
+	if first.GetAuthor().GetUserID() != "柯森杰" || first.GetUpvote() != 8000 {
+		t.Errorf("answer author is %s, upvote is %d", first.GetAuthor(), first.GetUpvote())
+	}
+	if !strings.Contains(first.GetContent(), `src="https://pic2.zhimg.com/5d1ab2c1e0d1f0f2_b.jpg"`) {
+		t.Errorf("answer content is %s", first.GetContent())
+	}
+
+	if !answers[1].GetAuthor().IsAnonymous() || answers[1].GetUpvote() != 432 {
+		t.Errorf("answer author is %s, upvote is %d", answers[1].GetAuthor(), answers[1].GetUpvote())
+	}
+	if answers[3].GetAuthor().GetUserID() != "李开复" || answers[3].GetUpvote() != 1000 {
+		t.Errorf("answer author is %s, upvote is %d", answers[3].GetAuthor(), answers[3].GetUpvote())
+	}
+}
+
+func Test_GetAllAnswersCtxCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	question := newQuestion(NewClient(nil), server.URL+"/question/41171543", "")
+	answers, err := question.GetAllAnswersCtx(ctx)
+	if !errors.Is(err, context.Canceled) || answers != nil {
+		t.Errorf("GetAllAnswersCtx with canceled context returns %v, %v", answers, err)
+	}
+}
+
+func Test_QuestionLogs(t *testing.T) {
+	question := newReplayQuestion(t)
+	logs := question.GetLogs()
+	if len(logs) != 24 {
+		t.Fatalf("GetLogs() returns %d logs, want 24", len(logs))
+	}
+
+	if l := logs[0]; l.Type != QuestionLogEditTitle || l.User.GetUserID() != "黄继新" || l.Old != "如何评价 AlphaGo 战胜李世石？" || l.New != "如何评价第一局比赛 AlphaGo 战胜李世石？" {
+		t.Errorf("logs[0] returns error result: %+v", l)
+	}
+	if l := logs[1]; l.Type != QuestionLogEditDetail || l.User.Link != "https://www.zhihu.com/people/zhouyuan" {
+		t.Errorf("logs[1] returns error result: %+v", l)
+	}
+	if l := logs[2]; l.Type != QuestionLogAddTopic || l.Topic == nil || l.Topic.GetName() != "围棋" || l.Topic.Link != "https://www.zhihu.com/topic/19552432" {
+		t.Errorf("logs[2] returns error result: %+v", l)
+	}
+	if l := logs[3]; l.Type != QuestionLogRemoveTopic || l.Topic == nil || l.Topic.GetName() != "体育" {
+		t.Errorf("logs[3] returns error result: %+v", l)
+	}
+	if l := logs[4]; l.Type != QuestionLogOther || l.User != ANONYMOUS || l.Action != "知乎管理员 锁定了问题" {
+		t.Errorf("logs[4] returns error result: %+v", l)
+	}
+	if l := logs[23]; l.Type != QuestionLogAsk || l.ID != 153460017 || l.New != "如何评价 AlphaGo 战胜李世石？" {
+		t.Errorf("logs[23] returns error result: %+v", l)
+	}
+
+	created := time.Date(2016, 3, 9, 13, 33, 12, 0, zhihuLocation)
+	if got := question.GetCreatedTime(); !got.Equal(created) {
+		t.Errorf("GetCreatedTime() returns %s, want %s", got, created)
+	}
+}
+
+func Test_QuestionRelatedAndRedirect(t *testing.T) {
+	question := newReplayQuestion(t)
+	related := question.GetRelatedQuestions()
+	if len(related) != 2 || related[0].GetTitle() != "如何评价第二局比赛 AlphaGo 再胜李世石？" || related[1].Link != "https://www.zhihu.com/question/19550000" {
+		t.Errorf("GetRelatedQuestions() returns error result: %v", related)
+	}
+	if question.IsClosed() || question.GetRedirectTarget() != nil {
+		t.Errorf("question should not be closed or redirected")
+	}
+
+	if !related[1].IsClosed() || related[1].GetRedirectTarget() != nil {
+		t.Errorf("%s should be closed and not redirected", related[1])
+	}
+
+	merged, err := newReplayClient().Question("https://www.zhihu.com/question/28966220", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target := merged.GetRedirectTarget(); target == nil || target.Link != "https://www.zhihu.com/question/19550000" || target.GetTitle() != "AlphaGo 会赢吗？" {
+		t.Errorf("GetRedirectTarget() returns error result: %v", target)
+	}
+}
+
+func Test_GetAllAnswersCtxAjaxError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/node/QuestionAnswerListV2" {
+			http.Error(w, "server error", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`<html><body><h3 id="zh-question-answer-num" data-num="40">40 个回答</h3></body></html>`))
+	}))
+	defer server.Close()
+
+	session, _ := NewSessionWithCookieStore(nil)
+	if err := session.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	question := newQuestion(NewClient(session), server.URL+"/question/41171543", "")
+	answers, err := question.GetAllAnswersCtx(context.Background())
+	if err == nil || len(answers) != 0 {
+		t.Errorf("GetAllAnswersCtx with failed Ajax page returns %v, %v, want error", answers, err)
 	}
 }
@@ -0,0 +1,210 @@
+package zhihu
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// QuestionLogType 是问题日志的类型
+type QuestionLogType int
+
+const (
+	// QuestionLogOther 是其他操作，如锁定、重定向问题，具体的操作见 QuestionLog.Action
+	QuestionLogOther QuestionLogType = iota
+
+	// QuestionLogAsk 添加了问题
+	QuestionLogAsk
+
+	// QuestionLogEditTitle 修改了标题，Old 和 New 是修改前后的标题
+	QuestionLogEditTitle
+
+	// QuestionLogEditDetail 修改了问题补充，Old 和 New 是修改前后的补充
+	QuestionLogEditDetail
+
+	// QuestionLogAddTopic 添加了话题，Topic 是添加的话题
+	QuestionLogAddTopic
+
+	// QuestionLogRemoveTopic 移除了话题，Topic 是移除的话题
+	QuestionLogRemoveTopic
+)
+
+// questionLogTypes 是日志里的操作描述对应的类型，按顺序匹配
+var questionLogTypes = []struct {
+	action  string
+	logType QuestionLogType
+}{
+	{"添加了问题补充", QuestionLogEditDetail},
+	{"修改了问题补充", QuestionLogEditDetail},
+	{"添加了问题", QuestionLogAsk},
+	{"修改了标题", QuestionLogEditTitle},
+	{"添加了话题", QuestionLogAddTopic},
+	{"移除了话题", QuestionLogRemoveTopic},
+}
+
+// String 返回类型的名称，如 edit_title
+func (t QuestionLogType) String() string {
+	switch t {
+	case QuestionLogOther:
+		return "other"
+	case QuestionLogAsk:
+		return "ask"
+	case QuestionLogEditTitle:
+		return "edit_title"
+	case QuestionLogEditDetail:
+		return "edit_detail"
+	case QuestionLogAddTopic:
+		return "add_topic"
+	case QuestionLogRemoveTopic:
+		return "remove_topic"
+	}
+	return fmt.Sprintf("QUESTION_LOG(%d)", int(t))
+}
+
+// QuestionLog 是问题日志里的一条记录，即谁在什么时候修改了问题的标题、补充或者话题
+type QuestionLog struct {
+	// ID 是日志的 ID
+	ID int64
+
+	// Type 是日志的类型
+	Type QuestionLogType
+
+	// Action 是页面上的操作描述，如“修改了标题”
+	Action string
+
+	// User 是操作的用户，没有用户链接时（如知乎管理员）为 ANONYMOUS
+	User *User
+
+	// Time 是操作的时间
+	Time time.Time
+
+	// Old 和 New 是修改前后的标题或者问题补充，其他类型为空
+	Old string
+	New string
+
+	// Topic 是添加或者移除的话题，其他类型为 nil
+	Topic *Topic
+}
+
+func (l *QuestionLog) String() string {
+	return fmt.Sprintf("<QuestionLog: %s - %s - %s>", l.Type, l.User.GetUserID(), l.Time.Format("2006-01-02 15:04:05"))
+}
+
+// GetLogsN 返回最新的 n 条问题日志，如果 n < 0，返回所有日志
+func (q *Question) GetLogsN(n int) []*QuestionLog {
+	logs, _ := q.GetLogsNCtx(context.Background(), n)
+	return logs
+}
+
+// GetLogsNCtx 同 GetLogsN，ctx 取消或超时后停止翻页并返回错误
+func (q *Question) GetLogsNCtx(ctx context.Context, n int) ([]*QuestionLog, error) {
+	return q.LogsIter(ctx).take(n)
+}
+
+// GetLogs 返回问题的所有日志，最新的在前面
+func (q *Question) GetLogs() []*QuestionLog {
+	return q.GetLogsN(-1)
+}
+
+// LogsIter 返回一个逐个获取问题日志的 Iterator，最新的在前面
+func (q *Question) LogsIter(ctx context.Context) *QuestionLogIterator {
+	return newIterator(ctx, "question.logs", q.Link, func(ctx context.Context, cursor *Cursor) ([]*QuestionLog, bool, error) {
+		if _, err := q.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
+
+		form := url.Values{}
+		form.Set("_xsrf", q.GetXSRF())
+		form.Set("offset", strconv.Itoa(cursor.Offset))
+		form.Set("start", strconv.FormatInt(cursor.Start, 10))
+		doc, dataNum, err := q.client.newDocByNormalAjax(ctx, urlJoin(q.Link, "/log"), form)
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := doc.Find("div.zm-item")
+		logs := make([]*QuestionLog, 0, items.Size())
+		items.Each(func(index int, sel *goquery.Selection) {
+			logs = append(logs, q.client.newQuestionLog(sel))
+		})
+		if len(logs) == 0 {
+			return logs, false, nil
+		}
+		cursor.Start = logs[len(logs)-1].ID
+		return logs, dataNum == pageSize, nil
+	})
+}
+
+// GetCreatedTime 返回问题的创建时间，即问题日志里“添加了问题”的时间，出错时返回零值，需要错误信息时请使用 GetCreatedTimeCtx。
+// 日志是从新到旧排列的，所以第一次调用时会翻完所有日志，编辑多的问题会比较慢
+func (q *Question) GetCreatedTime() time.Time {
+	created, _ := q.GetCreatedTimeCtx(context.Background())
+	return created
+}
+
+// GetCreatedTimeCtx 同 GetCreatedTime，ctx 取消或超时后停止翻页并返回错误；没有任何日志时返回 ErrParse
+func (q *Question) GetCreatedTimeCtx(ctx context.Context) (time.Time, error) {
+	if got, ok := q.getTimeField("created-time"); ok {
+		return got, nil
+	}
+
+	logs, err := q.GetLogsNCtx(ctx, -1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(logs) == 0 {
+		return time.Time{}, wrapError(ErrParse, "问题 %s 没有日志", q.Link)
+	}
+	created := logs[len(logs)-1].Time
+	for _, log := range logs {
+		if log.Type == QuestionLogAsk {
+			created = log.Time
+		}
+	}
+	q.setField("created-time", created)
+	return created, nil
+}
+
+// newQuestionLog 解析一条问题日志
+func (c *Client) newQuestionLog(sel *goquery.Selection) *QuestionLog {
+	id, _ := sel.Attr("id")
+	log := &QuestionLog{Type: QuestionLogOther, User: ANONYMOUS}
+	log.ID, _ = strconv.ParseInt(strings.TrimPrefix(id, "logitem-"), 10, 64)
+
+	head := sel.Children().First()
+	if a := head.Find(`a[href^="/people/"]`).First(); a.Size() > 0 {
+		href, _ := a.Attr("href")
+		log.User = newUser(c, c.makeZhihuLink(href), strip(a.Text()))
+		a.Remove()
+	}
+	log.Action = strip(head.Text())
+	for _, t := range questionLogTypes {
+		if strings.Contains(log.Action, t.action) {
+			log.Type = t.logType
+			break
+		}
+	}
+
+	datetime, _ := sel.Find("div.zm-item-meta time").Attr("datetime")
+	log.Time, _ = time.ParseInLocation("2006-01-02 15:04:05", datetime, zhihuLocation)
+
+	detail := sel.Find("div.zm-item-log-detail")
+	switch log.Type {
+	case QuestionLogEditTitle, QuestionLogEditDetail:
+		log.Old = strip(detail.Find("del").Text())
+		log.New = strip(detail.Find("ins").Text())
+	case QuestionLogAsk:
+		log.New = strip(detail.Find("ins").Text())
+	case QuestionLogAddTopic, QuestionLogRemoveTopic:
+		a := detail.Find(`a[href^="/topic/"]`).First()
+		if href, ok := a.Attr("href"); ok {
+			log.Topic = newTopic(c, c.makeZhihuLink(href), strip(a.Text()))
+		}
+	}
+	return log
+}
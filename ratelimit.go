@@ -0,0 +1,196 @@
+package zhihu
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RateLimit 是令牌桶的参数
+type RateLimit struct {
+	// Rate 是每秒产生的令牌数，即平均每秒允许发出的请求数，小于等于 0 表示不限制
+	Rate float64
+
+	// Burst 是令牌桶的容量，即最多允许连续发出的请求数，小于 1 时按 1 处理
+	Burst int
+
+	// Jitter 是每次请求前额外等待的随机时长的上限，让请求间隔不那么规律
+	Jitter time.Duration
+}
+
+// DefaultRateLimit 是比较礼貌的请求频率：平均每秒 1 个请求，最多连续 5 个，再加上最多 500ms 的随机等待
+var DefaultRateLimit = RateLimit{Rate: 1, Burst: 5, Jitter: 500 * time.Millisecond}
+
+// RateLimiter 限制请求频率，每个 host 使用一个单独的令牌桶，还可以设置每天的请求总数上限。
+// 用 Session.SetRateLimiter 设置后，Session 的 Get, Post, Ajax 等请求都会受到限制。可以被多个 Session 共用
+type RateLimiter struct {
+	mu         sync.Mutex
+	limit      RateLimit
+	hostLimits map[string]RateLimit
+	buckets    map[string]*tokenBucket
+
+	dailyBudget int    // 每天的请求总数上限，0 表示不限制
+	used        int    // 当天已经发出的请求数
+	today       string // 当天的日期，用于跨天后重置 used
+}
+
+// NewRateLimiter 创建一个 RateLimiter，limit 对所有 host 生效，可以用 SetHostLimit 为个别 host 单独设置
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:      limit,
+		hostLimits: make(map[string]RateLimit),
+		buckets:    make(map[string]*tokenBucket),
+	}
+}
+
+// SetHostLimit 为 host（如 "www.zhihu.com"）单独设置请求频率
+func (l *RateLimiter) SetHostLimit(host string, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hostLimits[host] = limit
+	delete(l.buckets, host)
+}
+
+// SetDailyBudget 设置每天（本地时间）最多发出的请求数，用完后请求会立即失败，返回 ErrBudgetExhausted。
+// n 为 0 表示不限制
+func (l *RateLimiter) SetDailyBudget(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dailyBudget = n
+}
+
+// Remaining 返回当天还可以发出的请求数，没有设置上限时返回 -1
+func (l *RateLimiter) Remaining() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dailyBudget <= 0 {
+		return -1
+	}
+	l.resetIfNewDay(time.Now())
+	return l.dailyBudget - l.used
+}
+
+// Wait 等待直到可以向 host 发出一个请求。
+// 当天的请求数已经用完时立即返回 ErrBudgetExhausted；ctx 取消或超时时返回 ctx.Err()，此时请求不会发出，占用的名额和令牌会被归还
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	r, err := l.reserve(host, time.Now())
+	if err != nil {
+		return err
+	}
+	if r.delay <= 0 {
+		err = ctx.Err()
+	} else {
+		err = sleepCtx(ctx, r.delay)
+	}
+	if err != nil {
+		l.cancel(r)
+	}
+	return err
+}
+
+// reservation 是 reserve 占用的一个请求名额
+type reservation struct {
+	delay  time.Duration // 需要等待的时长
+	day    string        // 计入了哪一天的请求数，为空表示没有计入
+	bucket *tokenBucket  // 从哪个令牌桶取走了令牌，为 nil 表示没有限制频率
+}
+
+// reserve 占用一个请求名额，请求最终没有发出时要用 cancel 归还
+func (l *RateLimiter) reserve(host string, now time.Time) (reservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var r reservation
+	if l.dailyBudget > 0 {
+		l.resetIfNewDay(now)
+		if l.used >= l.dailyBudget {
+			return r, wrapError(ErrBudgetExhausted, "今天已经发出了 %d 个请求", l.used)
+		}
+		l.used++
+		r.day = l.today
+	}
+
+	limit, ok := l.hostLimits[host]
+	if !ok {
+		limit = l.limit
+	}
+	if limit.Rate <= 0 {
+		return r, nil
+	}
+
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = newTokenBucket(limit, now)
+		l.buckets[host] = bucket
+	}
+
+	r.bucket = bucket
+	r.delay = bucket.take(now)
+	if limit.Jitter > 0 {
+		r.delay += time.Duration(rand.Int63n(int64(limit.Jitter)))
+	}
+	return r, nil
+}
+
+// cancel 归还 r 占用的名额和令牌，用于请求最终没有发出的情况。已经跨天的名额不需要归还
+func (l *RateLimiter) cancel(r reservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r.day != "" && r.day == l.today && l.used > 0 {
+		l.used--
+	}
+	if r.bucket != nil {
+		r.bucket.put()
+	}
+}
+
+func (l *RateLimiter) resetIfNewDay(now time.Time) {
+	today := now.Format("2006-01-02")
+	if today != l.today {
+		l.today = today
+		l.used = 0
+	}
+}
+
+// tokenBucket 是一个令牌桶，令牌数可以是负数，表示已经被后续的请求预订了
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: now}
+}
+
+// take 取走一个令牌，返回需要等待多久这个令牌才会产生
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// put 归还一个令牌
+func (b *tokenBucket) put() {
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
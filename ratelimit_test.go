@@ -0,0 +1,101 @@
+package zhihu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_tokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(RateLimit{Rate: 2, Burst: 2}, now)
+
+	ioMap := []struct {
+		elapsed time.Duration
+		delay   time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+		{0, time.Second},
+		{2 * time.Second, 0}, // 2 秒产生了 4 个令牌，补上之前预订的 2 个
+	}
+
+	for i, item := range ioMap {
+		now = now.Add(item.elapsed)
+		if got := bucket.take(now); got != item.delay {
+			t.Errorf("take #%d returns delay %v, want %v", i, got, item.delay)
+		}
+	}
+}
+
+func Test_RateLimiterDailyBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(RateLimit{})
+	limiter.SetDailyBudget(2)
+
+	s := NewSession()
+	s.SetRateLimiter(limiter)
+	for i := 0; i < 2; i++ {
+		resp, err := s.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request #%d returns error: %s", i, err.Error())
+		}
+		resp.Body.Close()
+	}
+
+	if _, err := s.Get(server.URL); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("request after budget exhausted returns %v, want ErrBudgetExhausted", err)
+	}
+	if limiter.Remaining() != 0 {
+		t.Errorf("Remaining returns %d, want 0", limiter.Remaining())
+	}
+}
+
+func Test_RateLimiterWaitCanceled(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 0.001, Burst: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "www.zhihu.com"); err != nil {
+		t.Fatalf("first Wait returns error: %s", err.Error())
+	}
+	if err := limiter.Wait(ctx, "www.zhihu.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second Wait returns %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func Test_RateLimiterWaitCanceledRefund(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 0.001, Burst: 1})
+	limiter.SetDailyBudget(2)
+
+	if err := limiter.Wait(context.Background(), "www.zhihu.com"); err != nil {
+		t.Fatalf("first Wait returns error: %s", err.Error())
+	}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		err := limiter.Wait(ctx, "www.zhihu.com")
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Wait #%d returns %v, want context.DeadlineExceeded", i, err)
+		}
+	}
+
+	// 取消的请求没有发出，不占用当天的名额，也不会把令牌桶越借越多
+	if got := limiter.Remaining(); got != 1 {
+		t.Errorf("Remaining returns %d after canceled waits, want 1", got)
+	}
+	limiter.mu.Lock()
+	tokens := limiter.buckets["www.zhihu.com"].tokens
+	limiter.mu.Unlock()
+	if tokens < -0.01 || tokens > 0.01 {
+		t.Errorf("bucket has %f tokens after canceled waits, want about 0", tokens)
+	}
+}
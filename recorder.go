@@ -0,0 +1,163 @@
+package zhihu
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecorderMode 是 Recorder 的工作模式
+type RecorderMode int
+
+const (
+	// ModeReplay 只从录制文件回放响应，不发出任何真实请求，找不到录制文件时返回错误
+	ModeReplay RecorderMode = iota
+
+	// ModeRecord 发出真实请求，并把响应保存为录制文件，已有的文件会被覆盖
+	ModeRecord
+
+	// ModeReplayOrRecord 有录制文件时回放，没有时发出真实请求并录制
+	ModeReplayOrRecord
+)
+
+// Recorder 是一个可以录制和回放 HTTP 响应的 http.RoundTripper，用 Session.SetTransport 安装后，
+// 可以把真实的页面保存下来，之后在没有网络的环境里（如 CI）回放，用于测试各个解析方法。
+//
+// 每个请求对应 Dir 下的一个文件，路径由请求的 host、path、method 组成，有查询参数或请求体时再加上它们的哈希值，如：
+//
+//	GET https://www.zhihu.com/question/41171543          -> www.zhihu.com/question/41171543/GET.http
+//	GET https://www.zhihu.com/people/jixin/asks?page=1   -> www.zhihu.com/people/jixin/asks/GET-6ba4a10c.http
+//
+// 文件内容是原始的 HTTP 响应（状态行、响应头、空行、响应体），没有 Content-Length，可以直接用编辑器修改。
+// 录制的响应里可能有 Set-Cookie 等敏感信息，提交之前记得检查
+type Recorder struct {
+	// Dir 是保存录制文件的目录
+	Dir string
+
+	// Mode 是工作模式，默认是 ModeReplay
+	Mode RecorderMode
+
+	// Transport 用于发出真实请求，为 nil 时使用 http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// NewRecorder 创建一个 Recorder，录制文件保存在 dir 目录下
+func NewRecorder(dir string, mode RecorderMode) *Recorder {
+	return &Recorder{Dir: dir, Mode: mode}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	filename, err := r.FixturePath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Mode != ModeRecord {
+		resp, err := r.replay(filename, req)
+		if err == nil || !os.IsNotExist(err) {
+			return resp, err
+		}
+		if r.Mode == ModeReplay {
+			return nil, fmt.Errorf("没有 %s %s 的录制文件：%s", req.Method, req.URL, filename)
+		}
+	}
+	return r.record(filename, req)
+}
+
+// FixturePath 返回 req 对应的录制文件的路径，会读取并恢复 req.Body
+func (r *Recorder) FixturePath(req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	name := req.Method
+	if req.URL.RawQuery != "" || len(body) > 0 {
+		hash := sha1.New()
+		hash.Write([]byte(req.URL.RawQuery))
+		hash.Write([]byte("\n"))
+		hash.Write(body)
+		name += "-" + hex.EncodeToString(hash.Sum(nil))[:8]
+	}
+
+	host := strings.Replace(req.URL.Host, ":", "_", -1)
+	path := filepath.FromSlash(strings.Trim(req.URL.Path, "/"))
+	return filepath.Join(r.Dir, host, path, name+".http"), nil
+}
+
+func (r *Recorder) replay(filename string, req *http.Request) (*http.Response, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(content)), req)
+	if err != nil {
+		return nil, fmt.Errorf("录制文件 %s 格式不正确：%s", filename, err.Error())
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取录制文件 %s 失败：%s", filename, err.Error())
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+func (r *Recorder) record(filename string, req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// 不保存 Content-Length 和 chunked 编码，响应体一直读到文件末尾，这样可以直接编辑录制文件
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = -1
+	resp.TransferEncoding = nil
+	resp.Close = true
+	resp.Header.Del("Content-Length")
+	resp.Header.Del("Content-Encoding")
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+	if err := save(filename, dump); err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
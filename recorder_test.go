@@ -0,0 +1,75 @@
+package zhihu
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+const fixturesDir = "testdata/fixtures"
+
+// newReplayClient 返回一个从 testdata/fixtures 回放响应的 Client，不会发出任何真实请求。
+// 这些响应是手写的，不是录制的，见 testdata/README.md
+func newReplayClient() *Client {
+	session, _ := NewSessionWithCookieStore(nil)
+	session.SetTransport(NewRecorder(fixturesDir, ModeReplay))
+	return NewClient(session)
+}
+
+func Test_RecorderFixturePath(t *testing.T) {
+	recorder := NewRecorder(fixturesDir, ModeReplay)
+	ioMap := map[string]string{
+		"https://www.zhihu.com":                          "testdata/fixtures/www.zhihu.com/GET.http",
+		"https://www.zhihu.com/question/41171543":        "testdata/fixtures/www.zhihu.com/question/41171543/GET.http",
+		"https://www.zhihu.com/people/jixin/asks?page=1": "testdata/fixtures/www.zhihu.com/people/jixin/asks/GET-6ba4a10c.http",
+	}
+
+	for link, expected := range ioMap {
+		req, _ := http.NewRequest("GET", link, nil)
+		if got, _ := recorder.FixturePath(req); got != expected {
+			t.Errorf("FixturePath(%s) returns %s, want %s", link, got, expected)
+		}
+	}
+}
+
+func Test_RecorderRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "zhihu-recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	record := &http.Client{Transport: NewRecorder(dir, ModeRecord)}
+	resp, err := record.Post(server.URL+"/node/QuestionAnswerListV2", "text/plain", strings.NewReader("offset=20"))
+	if err != nil {
+		t.Fatalf("record returns error: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	server.Close() // 回放时不会再访问服务器
+	replay := &http.Client{Transport: NewRecorder(dir, ModeReplay)}
+	resp, err = replay.Post(server.URL+"/node/QuestionAnswerListV2", "text/plain", strings.NewReader("offset=20"))
+	if err != nil {
+		t.Fatalf("replay returns error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if string(body) != "POST /node/QuestionAnswerListV2 offset=20" || resp.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("replay returns %q, %v", body, resp.Header)
+	}
+
+	if _, err := replay.Post(server.URL+"/node/QuestionAnswerListV2", "text/plain", strings.NewReader("offset=40")); err == nil {
+		t.Error("replay without fixture should fail")
+	}
+}
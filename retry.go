@@ -0,0 +1,111 @@
+package zhihu
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 是请求失败后自动重试的策略。
+// 网络错误、429 和 5xx 响应会被重试，等待时长从 BaseDelay 开始每次翻倍，不超过 MaxDelay；
+// 如果响应带有 Retry-After 头，则按它的要求等待（同样不超过 MaxDelay）
+type RetryPolicy struct {
+	// MaxRetries 是最多重试的次数，不包括第一次请求
+	MaxRetries int
+
+	// BaseDelay 是第一次重试前等待的时长
+	BaseDelay time.Duration
+
+	// MaxDelay 是每次重试前等待时长的上限，0 表示不限制
+	MaxDelay time.Duration
+
+	// OnRetry 在每次重试前调用，attempt 从 1 开始，resp 和 err 是上一次请求的结果（resp.Body 已经关闭），
+	// delay 是接下来要等待的时长。可以用来统计重试次数或记录日志
+	OnRetry func(attempt int, req *http.Request, resp *http.Response, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy 最多重试 3 次，分别等待 0.5s, 1s, 2s，最长等待 30s
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+type retryNonIdempotentKey struct{}
+
+// WithRetryNonIdempotent 返回一个新的 ctx，用它发起的 POST 等非幂等请求失败后也会重试。
+// 默认只重试 GET, HEAD 等幂等的请求，只有确定请求可以重复发送时才使用
+func WithRetryNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryNonIdempotentKey{}, true)
+}
+
+// canRetry 判断 req 失败后是否可以重新发送
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false // 请求体无法重新读取
+	}
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	optIn, _ := req.Context().Value(retryNonIdempotentKey{}).(bool)
+	return optIn
+}
+
+// shouldRetry 判断请求结果是否属于临时性的失败
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil || errors.Is(err, ErrBudgetExhausted) {
+			return false
+		}
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff 返回第 attempt 次重试前需要等待的时长
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	// 翻倍后会溢出时取最大值，再由 MaxDelay 限制；MaxDelay 为 0 时不能退化成 0，否则会不停地重试
+	delay := time.Duration(math.MaxInt64)
+	if shift := uint(attempt - 1); shift < 63 && p.BaseDelay <= delay>>shift {
+		delay = p.BaseDelay << shift
+	}
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			delay = after
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter 解析 Retry-After 头，它可以是秒数，也可以是一个 HTTP 日期
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// sleepCtx 等待 d，ctx 取消或超时时提前返回 ctx.Err()
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package zhihu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	ioMap := map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Tue, 01 Mar 2016 12:00:30 GMT": 30 * time.Second,
+		"Tue, 01 Mar 2016 11:00:00 GMT": 0,
+	}
+
+	for value, expected := range ioMap {
+		got, ok := parseRetryAfter(value, now)
+		if !ok || got != expected {
+			t.Errorf("parseRetryAfter(%q) returns %v, %v, want %v", value, got, ok, expected)
+		}
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Errorf("parseRetryAfter(%q) should fail", "soon")
+	}
+}
+
+func Test_RetryPolicyBackoff(t *testing.T) {
+	unlimited := RetryPolicy{BaseDelay: time.Second}
+	if got := unlimited.backoff(3, nil); got != 4*time.Second {
+		t.Errorf("backoff(3) returns %s, want 4s", got)
+	}
+	for _, attempt := range []int{35, 64, 65, 1000} {
+		if got := unlimited.backoff(attempt, nil); got <= 0 {
+			t.Errorf("backoff(%d) without MaxDelay returns %s, want a positive delay", attempt, got)
+		}
+	}
+
+	capped := RetryPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+	for _, attempt := range []int{6, 35, 64, 1000} {
+		if got := capped.backoff(attempt, nil); got != 30*time.Second {
+			t.Errorf("backoff(%d) returns %s, want 30s", attempt, got)
+		}
+	}
+}
+
+func Test_RetryPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	retries := 0
+	s := NewSession()
+	s.SetRetryPolicy(&RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		OnRetry: func(attempt int, req *http.Request, resp *http.Response, err error, delay time.Duration) {
+			retries = attempt
+		},
+	})
+
+	resp, err := s.Get(server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Get returns %v, %v", resp, err)
+	}
+	resp.Body.Close()
+	if requests != 3 || retries != 2 {
+		t.Errorf("got %d requests and %d retries, want 3 and 2", requests, retries)
+	}
+
+	// 没有明确允许时，POST 请求不重试
+	requests = 0
+	resp, err = s.Post(server.URL, "text/plain", strings.NewReader("body"))
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || requests != 1 {
+		t.Errorf("Post returns %v, %v after %d requests", resp, err, requests)
+	}
+
+	requests = 0
+	ctx := WithRetryNonIdempotent(context.Background())
+	resp, err = s.PostCtx(ctx, server.URL, "text/plain", strings.NewReader("body"))
+	if err != nil || resp.StatusCode != http.StatusOK || requests != 3 {
+		t.Errorf("PostCtx with WithRetryNonIdempotent returns %v, %v after %d requests", resp, err, requests)
+	}
+}
@@ -1,14 +1,15 @@
 package zhihu
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -23,7 +24,7 @@ type Auth struct {
 	Password string `json:"password"`
 
 	loginType string // phone_num 或 email
-	loginURL  string // 通过 Account 判断
+	loginPath string // 登录地址的路径，通过 Account 判断
 }
 
 // isEmail 判断是否通过邮箱登录
@@ -36,28 +37,37 @@ func (auth *Auth) isPhone() bool {
 	return regexp.MustCompile(`^1[0-9]{10}$`).MatchString(auth.Account)
 }
 
-func (auth *Auth) toForm() url.Values {
+func (auth *Auth) toForm() (url.Values, error) {
 	if auth.isEmail() {
 		auth.loginType = "email"
-		auth.loginURL = makeZhihuLink("/login/email")
+		auth.loginPath = "/login/email"
 	} else if auth.isPhone() {
 		auth.loginType = "phone_num"
-		auth.loginURL = makeZhihuLink("/login/phone_num")
+		auth.loginPath = "/login/phone_num"
 	} else {
-		panic("无法判断登录类型: " + auth.Account)
+		return nil, fmt.Errorf("无法判断登录类型: %s", auth.Account)
 	}
 	values := url.Values{}
-	logger.Info("登录类型：%s, 登录地址：%s", auth.loginType, auth.loginURL)
 	values.Set(auth.loginType, auth.Account)
 	values.Set("password", auth.Password)
 	values.Set("remember_me", "true") // import!
-	return values
+	return values, nil
 }
 
 // Session 保持和知乎服务器的会话，用于向服务器发起请求获取 HTML 或 JSON 数据
 type Session struct {
-	auth   *Auth
-	client *http.Client
+	auth          *Auth
+	client        *http.Client
+	jar           *cookiejar.Jar
+	cookieStore   CookieStore
+	rateLimiter   *RateLimiter
+	retryPolicy   *RetryPolicy
+	concurrency   int
+	baseURL       *url.URL
+	columnBaseURL *url.URL
+	hosts         []string
+	logger        Logger
+	captchaSolver CaptchaSolver
 }
 
 type loginResult struct {
@@ -68,12 +78,59 @@ type loginResult struct {
 }
 
 // NewSession 创建并返回一个 *Session 对象，
-// 这里没有初始化登录账号信息，账号信息用 `LoadConfig` 通过配置文件进行设置
+// 这里没有初始化登录账号信息，账号信息用 `LoadConfig` 通过配置文件进行设置。
+// cookies 保存在 persistent-cookiejar 默认的文件里（$HOME/.go-cookies），如果该文件无法读取，则只保存在内存中
 func NewSession() *Session {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		logger.Warn("载入 cookies 失败，cookies 将只保存在内存中", "err", err)
+		jar, _ = cookiejar.New(&cookiejar.Options{NoPersist: true})
+	}
+	return newSessionWithJar(jar, nil)
+}
+
+// NewSessionWithCookieFile 创建一个 *Session 对象，cookies 保存在 filename 指定的文件里，
+// 可以用不同的文件区分多个账号
+func NewSessionWithCookieFile(filename string) (*Session, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{Filename: filename})
+	if err != nil {
+		return nil, fmt.Errorf("载入 cookies 文件 %s 失败：%s", filename, err.Error())
+	}
+	return newSessionWithJar(jar, nil), nil
+}
+
+// NewSessionWithCookieStore 创建一个 *Session 对象，cookies 从 store 载入，并保存到 store 里，不会读写任何文件。
+// store 为 nil 时使用 MemoryCookieStore
+func NewSessionWithCookieStore(store CookieStore) (*Session, error) {
+	if store == nil {
+		store = new(MemoryCookieStore)
+	}
+
+	jar, err := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("载入 cookies 失败：%s", err.Error())
+	}
+
+	s := newSessionWithJar(jar, store)
+	s.setCookies(cookies)
+	return s, nil
+}
+
+func newSessionWithJar(jar *cookiejar.Jar, store CookieStore) *Session {
 	s := new(Session)
-	cookieJar, _ := cookiejar.New(nil)
+	s.logger = logger
+	s.captchaSolver = &InteractiveCaptchaSolver{}
+	s.jar = jar
+	s.cookieStore = store
+	s.SetBaseURL(baseZhihuURL)
+	s.SetColumnBaseURL(baseColumnURL)
 	s.client = &http.Client{
-		Jar: cookieJar,
+		Jar: jar,
 	}
 	return s
 }
@@ -84,210 +141,394 @@ func NewSession() *Session {
 //   "account": "xyz@example.com",
 //   "password": "p@ssw0rd"
 // }
-func (s *Session) LoadConfig(cfg string) {
+func (s *Session) LoadConfig(cfg string) error {
 	fd, err := os.Open(cfg)
 	if err != nil {
-		panic("无法打开配置文件 config.json: " + err.Error())
+		return fmt.Errorf("无法打开配置文件 %s: %s", cfg, err.Error())
 	}
 	defer fd.Close()
 
 	auth := new(Auth)
 	err = json.NewDecoder(fd).Decode(&auth)
 	if err != nil {
-		panic("解析配置文件出错: " + err.Error())
+		return fmt.Errorf("解析配置文件出错: %s", err.Error())
 	}
 
 	s.auth = auth
 	// TODO 如果设置了与上一次不一样的账号，最好把 cookies 重置
+	return nil
+}
+
+// SetCaptchaSolver 设置登录时识别验证码的方式，默认是 InteractiveCaptchaSolver
+func (s *Session) SetCaptchaSolver(solver CaptchaSolver) {
+	s.captchaSolver = solver
+}
+
+// SetRateLimiter 设置请求频率限制，Get, Post, Ajax 等请求发出前都会先经过 limiter，nil 表示不限制（默认）
+func (s *Session) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetConcurrency 设置翻页时最多同时请求多少页，默认是 1，即逐页请求。
+// 只对页码事先知道的列表生效，如问题的回答、收藏夹的问题和回答、用户的提问、回答和收藏夹；
+// 返回的顺序不变，每个请求仍然会经过 RateLimiter
+func (s *Session) SetConcurrency(n int) {
+	s.concurrency = n
+}
+
+// SetRetryPolicy 设置请求失败后的重试策略，nil 表示不重试（默认）。
+// 只有 GET 等幂等的请求会被重试，POST 请求需要用 WithRetryNonIdempotent 包装 ctx 才会重试
+func (s *Session) SetRetryPolicy(policy *RetryPolicy) {
+	s.retryPolicy = policy
+}
+
+// SetTransport 设置发出请求使用的 http.RoundTripper，如 Recorder，nil 表示使用 http.DefaultTransport
+func (s *Session) SetTransport(transport http.RoundTripper) {
+	s.client.Transport = transport
+}
+
+// SetBaseURL 设置知乎的地址，默认是 https://www.zhihu.com，可以指向本地的 mock server 或者镜像站点。
+// 所有站内链接都基于它拼接，创建 Question, Collection, Topic 时只接受 host 是 base 或 hosts 之一的链接
+func (s *Session) SetBaseURL(base string, hosts ...string) error {
+	u, err := url.Parse(strings.TrimRight(base, "/"))
+	if err != nil {
+		return wrapError(ErrInvalidURL, "%s", err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return wrapError(ErrInvalidURL, "BaseURL 必须是 http(s) 链接：%s", base)
+	}
+
+	s.baseURL = u
+	s.hosts = append([]string{u.Host}, hosts...)
+	return nil
+}
+
+// BaseURL 返回知乎的地址，见 SetBaseURL
+func (s *Session) BaseURL() string {
+	return s.baseURL.String()
+}
+
+// SetColumnBaseURL 设置知乎专栏的地址，默认是 https://zhuanlan.zhihu.com，可以带路径前缀，如 http://127.0.0.1:8080/zhuanlan。
+// 专栏、专栏文章的链接和接口都基于它拼接
+func (s *Session) SetColumnBaseURL(base string) error {
+	u, err := url.Parse(strings.TrimRight(base, "/"))
+	if err != nil {
+		return wrapError(ErrInvalidURL, "%s", err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return wrapError(ErrInvalidURL, "专栏地址必须是 http(s) 链接：%s", base)
+	}
+
+	s.columnBaseURL = u
+	return nil
+}
+
+// ColumnBaseURL 返回知乎专栏的地址，见 SetColumnBaseURL
+func (s *Session) ColumnBaseURL() string {
+	return s.columnBaseURL.String()
+}
+
+// columnPath 判断 u 是否是专栏的链接，是则返回相对于专栏地址的路径，如 /p/20761239。
+// zhuanlan.zhihu.com 的链接总是被接受
+func (s *Session) columnPath(u *url.URL) (string, bool) {
+	if strings.EqualFold(u.Host, columnHost) && u.Path != "" {
+		return u.Path, true
+	}
+	if !strings.EqualFold(u.Host, s.columnBaseURL.Host) {
+		return "", false
+	}
+	prefix := s.columnBaseURL.Path
+	if prefix != "" && u.Path != prefix && !strings.HasPrefix(u.Path, prefix+"/") {
+		return "", false
+	}
+	path := strings.TrimPrefix(u.Path, prefix)
+	return path, path != ""
+}
+
+// acceptHost 判断 host 是否是 SetBaseURL 设置的 host 之一
+func (s *Session) acceptHost(host string) bool {
+	for _, h := range s.hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
 }
 
 // Login 登录并保存 cookies
 func (s *Session) Login() error {
-	if s.authenticated() {
-		logger.Success("已经是登录状态，不需要重复登录")
+	ok, err := s.authenticated()
+	if err != nil {
+		return err
+	}
+	if ok {
+		s.logger.Info("已经是登录状态，不需要重复登录")
 		return nil
 	}
 
-	form := s.buildLoginForm().Encode()
+	if s.auth == nil {
+		return wrapError(ErrLoginRequired, "没有设置账号信息，请先调用 LoadConfig")
+	}
+
+	values, err := s.buildLoginForm()
+	if err != nil {
+		s.logger.Error("构造登录表单失败", "err", err)
+		return err
+	}
+
+	form := values.Encode()
 	body := strings.NewReader(form)
-	req, err := http.NewRequest("POST", s.auth.loginURL, body)
+	req, err := http.NewRequest("POST", s.makeZhihuLink(s.auth.loginPath), body)
 	if err != nil {
-		logger.Error("构造登录请求失败：%s", err.Error())
+		s.logger.Error("构造登录请求失败", "err", err)
 		return err
 	}
 
-	headers := newHTTPHeaders(true)
+	headers := s.newHTTPHeaders(true)
 	headers.Set("Content-Length", strconv.Itoa(len(form)))
 	headers.Set("Content-Type", "application/x-www-form-urlencoded")
-	headers.Set("Referer", baseZhihuURL)
+	headers.Set("Referer", s.baseURL.String())
 	req.Header = headers
 
-	logger.Info("登录中，用户名：%s", s.auth.Account)
+	s.logger.Info("登录中", "account", s.auth.Account)
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
-		logger.Error("登录失败：%s", err.Error())
+		s.logger.Error("登录失败", "err", err)
 		return err
 	}
 
 	if strings.ToLower(resp.Header.Get("Content-Type")) != "application/json" {
-		logger.Error("服务器没有返回 json 数据")
-		return fmt.Errorf("未知的 Content-Type: %s", resp.Header.Get("Content-Type"))
+		s.logger.Error("服务器没有返回 json 数据", "content_type", resp.Header.Get("Content-Type"))
+		resp.Body.Close()
+		return wrapError(ErrParse, "未知的 Content-Type: %s", resp.Header.Get("Content-Type"))
 	}
 
 	defer resp.Body.Close()
 	result := loginResult{}
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logger.Error("读取响应内容失败：%s", err.Error())
+		s.logger.Error("读取响应内容失败", "err", err)
+		return err
 	}
 
-	logger.Info("登录响应内容：%s", strings.Replace(string(content), "\n", "", -1))
+	s.logger.Debug("登录响应", "content", strings.Replace(string(content), "\n", "", -1))
 
 	err = json.Unmarshal(content, &result)
 	if err != nil {
-		logger.Error("JSON 解析失败：%s", err.Error())
-		return err
+		s.logger.Error("JSON 解析失败", "err", err)
+		return wrapError(ErrParse, "%s", err.Error())
 	}
 
 	if result.R == 0 {
-		logger.Success("登录成功！")
-		s.client.Jar.(*cookiejar.Jar).Save()
-		return nil
+		s.logger.Info("登录成功", "account", s.auth.Account)
+		return s.saveCookies()
 	}
 	if result.R == 1 {
-		logger.Warn("登录失败！原因：%s", result.Msg)
+		s.logger.Warn("登录失败", "reason", result.Msg, "errcode", result.ErrorCode)
 		return fmt.Errorf("登录失败！原因：%s", result.Msg)
 	}
 
-	logger.Error("登录出现未知错误：%s", string(content))
+	s.logger.Error("登录出现未知错误", "content", string(content))
 	return fmt.Errorf("登录失败，未知错误：%s", string(content))
 }
 
 // Get 发起一个 GET 请求，自动处理 cookies
 func (s *Session) Get(url string) (*http.Response, error) {
-	logger.Info("GET %s", url)
-	req, err := http.NewRequest("GET", url, nil)
+	return s.GetCtx(context.Background(), url)
+}
+
+// GetCtx 同 Get，ctx 取消或超时后请求会被中止
+func (s *Session) GetCtx(ctx context.Context, url string) (*http.Response, error) {
+	s.logger.Debug("GET", "url", url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		logger.Error("NewRequest failed with URL: %s", url)
+		s.logger.Error("构造请求失败", "url", url, "err", err)
 		return nil, err
 	}
 
-	req.Header = newHTTPHeaders(false)
-	return s.client.Do(req)
+	req.Header = s.newHTTPHeaders(false)
+	return s.do(req)
 }
 
 // Post 发起一个 POST 请求，自动处理 cookies
 func (s *Session) Post(url string, bodyType string, body io.Reader) (*http.Response, error) {
-	logger.Info("POST %s, %s", url, bodyType)
-	req, err := http.NewRequest("POST", url, body)
+	return s.PostCtx(context.Background(), url, bodyType, body)
+}
+
+// PostCtx 同 Post，ctx 取消或超时后请求会被中止
+func (s *Session) PostCtx(ctx context.Context, url string, bodyType string, body io.Reader) (*http.Response, error) {
+	s.logger.Debug("POST", "url", url, "content_type", bodyType)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	headers := newHTTPHeaders(false)
+	headers := s.newHTTPHeaders(false)
 	headers.Set("Content-Type", bodyType)
 	req.Header = headers
-	return s.client.Do(req)
+	return s.do(req)
 }
 
 // Ajax 发起一个 Ajax 请求，自动处理 cookies
 func (s *Session) Ajax(url string, body io.Reader, referer string) (*http.Response, error) {
-	logger.Info("AJAX %s, referrer %s", url, referer)
-	req, err := http.NewRequest("POST", url, body)
+	return s.AjaxCtx(context.Background(), url, body, referer)
+}
+
+// AjaxCtx 同 Ajax，ctx 取消或超时后请求会被中止
+func (s *Session) AjaxCtx(ctx context.Context, url string, body io.Reader, referer string) (*http.Response, error) {
+	s.logger.Debug("AJAX", "url", url, "referer", referer)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	headers := newHTTPHeaders(true)
+	headers := s.newHTTPHeaders(true)
 	headers.Set("Content-Type", "application/x-www-form-urlencoded")
 	headers.Set("Referer", referer)
 	req.Header = headers
+	return s.do(req)
+}
+
+// do 发出请求，如果设置了 RateLimiter，先等待它放行；如果设置了 RetryPolicy，临时性的失败会自动重试
+func (s *Session) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.doOnce(req)
+	policy := s.retryPolicy
+	if policy == nil || !canRetry(req) {
+		return resp, err
+	}
+
+	for attempt := 1; attempt <= policy.MaxRetries && shouldRetry(req, resp, err); attempt++ {
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		delay := policy.backoff(attempt, resp)
+		if err != nil {
+			s.logger.Warn("请求失败，稍后重试", "method", req.Method, "url", req.URL, "err", err, "delay", delay, "attempt", attempt)
+		} else {
+			s.logger.Warn("请求失败，稍后重试", "method", req.Method, "url", req.URL, "status", resp.StatusCode, "delay", delay, "attempt", attempt)
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, req, resp, err, delay)
+		}
+		if err := sleepCtx(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err = s.doOnce(req)
+	}
+	return resp, err
+}
+
+func (s *Session) doOnce(req *http.Request) (*http.Response, error) {
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.Wait(req.Context(), req.URL.Host); err != nil {
+			s.logger.Warn("请求被限流", "method", req.Method, "url", req.URL, "err", err)
+			return nil, err
+		}
+	}
 	return s.client.Do(req)
 }
 
-// authenticated 检查是否已经登录（cookies 没有失效）
-func (s *Session) authenticated() bool {
-	originURL := makeZhihuLink("/settings/profile")
+// authenticated 检查是否已经登录（cookies 没有失效）。服务器表示没有登录时返回 false，
+// 网络错误、5xx 等无法判断的情况返回对应的错误
+func (s *Session) authenticated() (bool, error) {
+	originURL := s.makeZhihuLink("/settings/profile")
 	resp, err := s.Get(originURL)
 	if err != nil {
-		logger.Error("访问 profile 页面出错: %s", err.Error())
-		return false
+		s.logger.Error("访问 profile 页面出错", "err", err)
+		return false, err
+	}
+	if err := checkResponse(resp); err != nil {
+		if errors.Is(err, ErrLoginRequired) {
+			return false, nil
+		}
+		s.logger.Error("访问 profile 页面出错", "err", err)
+		return false, err
 	}
+	resp.Body.Close()
 
 	// 如果没有登录，会跳转到 http://www.zhihu.com/?next=%2Fsettings%2Fprofile
 	lastURL := resp.Request.URL.String()
-	logger.Info("获取 profile 的请求，跳转到了：%s", lastURL)
-	return lastURL == originURL
+	s.logger.Debug("获取 profile 的请求发生了跳转", "url", lastURL)
+	return lastURL == originURL, nil
 }
 
-func (s *Session) buildLoginForm() url.Values {
-	values := s.auth.toForm()
-	values.Set("_xsrf", s.searchXSRF())
-	values.Set("captcha", s.downloadCaptcha())
-	return values
+func (s *Session) buildLoginForm() (url.Values, error) {
+	values, err := s.auth.toForm()
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Debug("构造登录表单", "type", s.auth.loginType, "url", s.makeZhihuLink(s.auth.loginPath))
+
+	xsrf, err := s.searchXSRF()
+	if err != nil {
+		return nil, err
+	}
+	values.Set("_xsrf", xsrf)
+
+	captcha, err := s.downloadCaptcha()
+	if err != nil {
+		return nil, err
+	}
+	values.Set("captcha", captcha)
+	return values, nil
 }
 
 // 从 cookies 获取 _xsrf 用于 POST 请求
-func (s *Session) searchXSRF() string {
-	resp, err := s.Get(baseZhihuURL)
+func (s *Session) searchXSRF() (string, error) {
+	resp, err := s.Get(s.baseURL.String())
 	if err != nil {
-		panic("获取 _xsrf 失败：" + err.Error())
+		return "", fmt.Errorf("获取 _xsrf 失败：%s", err.Error())
 	}
+	resp.Body.Close()
 
 	// retrieve from cookies
 	for _, cookie := range resp.Cookies() {
 		if cookie.Name == "_xsrf" {
-			return cookie.Value
+			return cookie.Value, nil
 		}
 	}
 
-	return ""
+	return "", nil
 }
 
 // downloadCaptcha 获取验证码，用于登录
-func (s *Session) downloadCaptcha() string {
-	url := makeZhihuLink(fmt.Sprintf("/captcha.gif?r=%d&type=login", 1000*time.Now().Unix()))
-	logger.Info("获取验证码：%s", url)
+func (s *Session) downloadCaptcha() (string, error) {
+	url := s.makeZhihuLink(fmt.Sprintf("/captcha.gif?r=%d&type=login", 1000*time.Now().Unix()))
+	s.logger.Debug("获取验证码", "url", url)
 	resp, err := s.Get(url)
 	if err != nil {
-		panic("获取验证码失败：" + err.Error())
+		return "", fmt.Errorf("获取验证码失败：%s", err.Error())
 	}
-	if resp.StatusCode != http.StatusOK {
-		panic(fmt.Sprintf("获取验证码失败，StatusCode = %d", resp.StatusCode))
+	if err = checkResponse(resp); err != nil {
+		return "", fmt.Errorf("获取验证码失败：%w", err)
 	}
 
 	defer resp.Body.Close()
-
-	fileExt := strings.Split(resp.Header.Get("Content-Type"), "/")[1]
-	verifyImg := filepath.Join(getCwd(), "verify."+fileExt)
-	fd, err := os.OpenFile(verifyImg, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	img, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		panic("打开验证码文件失败：" + err.Error())
+		return "", fmt.Errorf("读取验证码失败：%s", err.Error())
 	}
-	defer fd.Close()
-
-	io.Copy(fd, resp.Body)        // 保存验证码文件
-	openCaptchaFile(verifyImg)    // 调用外部程序打开
-	captcha := readCaptchaInput() // 读取用户输入
-
-	return captcha
-}
-
-var (
-	gSession = NewSession() // 全局的 Session，调用 Init() 初始化
-)
-
-// Init 用于传入配置文件，配置全局的 Session
-func Init(cfgFile string) {
-	// 配置账号信息
-	gSession.LoadConfig(cfgFile)
-
-	// 登录
-	gSession.Login()
-}
 
-// SetSession 用于替换默认的 session
-func SetSession(s *Session) {
-	gSession = s
+	solver := s.captchaSolver
+	if interactive, ok := solver.(*InteractiveCaptchaSolver); ok && interactive.Logger == nil {
+		// 默认的 InteractiveCaptchaSolver 使用 Session 的 Logger，它可能在创建之后被 Client.SetLogger 替换
+		withLogger := *interactive
+		withLogger.Logger = s.logger
+		solver = &withLogger
+	}
+	captcha, err := solver.Solve(img)
+	if err != nil {
+		return "", fmt.Errorf("识别验证码失败：%s", err.Error())
+	}
+	return captcha, nil
 }
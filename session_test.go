@@ -1,14 +1,22 @@
 package zhihu
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
-const cfgFile = "./examples/config.json"
-
 func Test_searchXsrf(t *testing.T) {
-	s := NewSession()
-	logger.Debug("_xsrf: %s", s.searchXSRF())
+	s := newReplayClient().Session()
+	xsrf, err := s.searchXSRF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xsrf != "2b5e8a0fc9d4f2c1a6e3b7d8e9f01234" {
+		t.Errorf("searchXSRF returns %s", xsrf)
+	}
 }
 
 //func Test_downloadCaptcha(t *testing.T) {
@@ -22,3 +30,18 @@ func Test_searchXsrf(t *testing.T) {
 //	values := s.buildLoginForm()
 //	fmt.Println(values.Encode())
 //}
+
+func Test_GetCtxCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := NewSession()
+	if _, err := s.GetCtx(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetCtx with canceled context returns %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package zhihu
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -12,27 +13,36 @@ type Topic struct {
 
 	// name 是改话题的名称
 	name string
+
+	// organize 是话题的组织页面 /topic/{id}/organize，父话题和子话题在这个页面上
+	organize *Page
 }
 
+// NewTopic 通过给定的 URL 创建一个 Topic 对象，链接不合法时会 panic。
+//
+// Deprecated: 请使用 Client.Topic（如 DefaultClient().Topic），链接不合法时返回 ErrInvalidURL 而不是 panic
 func NewTopic(link string, name string) *Topic {
-	if !validTopicURL(link) {
-		panic("非法的 Topic 链接：%s" + link)
+	topic, err := defaultClient.Topic(link, name)
+	if err != nil {
+		panic("话题链接不正确：" + link)
 	}
+	return topic
+}
+
+func newTopic(client *Client, link string, name string) *Topic {
 	return &Topic{
-		Page: newZhihuPage(link),
-		name: name,
+		Page:     newZhihuPage(client, link),
+		name:     name,
+		organize: newZhihuPage(client, urlJoin(link, "/organize")),
 	}
 }
 
 // GetName 返回话题名称
 func (t *Topic) GetName() string {
-	if t.name != "" {
-		return t.name
-	}
-
-	// <h1 class="zm-editable-content" data-disabled="1">Python</h1>
-	t.name = strip(t.Doc().Find("h1.zm-editable-content").Text())
-	return t.name
+	return lazyField(t.Page, &t.name, func() string {
+		// <h1 class="zm-editable-content" data-disabled="1">Python</h1>
+		return strip(t.Doc().Find("h1.zm-editable-content").Text())
+	})
 }
 
 // GetDescription 返回话题的描述
@@ -78,7 +88,7 @@ func (t *Topic) GetTopAuthors() []*User {
 		uHref, _ := tag.Attr("href")
 		uId := strip(tag.Text())
 
-		thisAuthor := NewUser(makeZhihuLink(uHref), uId)
+		thisAuthor := newUser(t.client, t.client.makeZhihuLink(uHref), uId)
 
 		bio, _ := sel.Find("div.zm-topic-side-bio").Attr("title")
 		thisAuthor.setBio(bio)
@@ -88,6 +98,83 @@ func (t *Topic) GetTopAuthors() []*User {
 	return authors
 }
 
+// GetParents 返回父话题，来自话题的组织页面
+func (t *Topic) GetParents() []*Topic {
+	topics, _ := t.GetParentsCtx(context.Background())
+	return topics
+}
+
+// GetParentsCtx 同 GetParents，组织页面载入失败时返回错误
+func (t *Topic) GetParentsCtx(ctx context.Context) ([]*Topic, error) {
+	return t.getOrganizeTopics(ctx, "div#zh-topic-organize-parent-editor")
+}
+
+// GetChildren 返回子话题，来自话题的组织页面
+func (t *Topic) GetChildren() []*Topic {
+	topics, _ := t.GetChildrenCtx(context.Background())
+	return topics
+}
+
+// GetChildrenCtx 同 GetChildren，组织页面载入失败时返回错误
+func (t *Topic) GetChildrenCtx(ctx context.Context) ([]*Topic, error) {
+	return t.getOrganizeTopics(ctx, "div#zh-topic-organize-child-editor")
+}
+
+// WalkTopicTree 从 root 开始按广度优先遍历子话题，对每个话题调用一次 fn，depth 是话题到 root 的距离，root 是 0。
+// maxDepth 小于 0 时不限制深度；一个话题可以有多个父话题，已经访问过的话题会被跳过，所以每个话题只会出现一次。
+// fn 返回错误、ctx 取消或者子话题载入失败时，停止遍历并返回该错误
+func WalkTopicTree(ctx context.Context, root *Topic, maxDepth int, fn func(topic *Topic, depth int) error) error {
+	type node struct {
+		topic *Topic
+		depth int
+	}
+
+	visited := map[string]bool{root.Link: true}
+	queue := []node{{root, 0}}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		current := queue[0]
+		queue = queue[1:]
+
+		if err := fn(current.topic, current.depth); err != nil {
+			return err
+		}
+		if maxDepth >= 0 && current.depth >= maxDepth {
+			continue
+		}
+
+		children, err := current.topic.GetChildrenCtx(ctx)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if !visited[child.Link] {
+				visited[child.Link] = true
+				queue = append(queue, node{child, current.depth + 1})
+			}
+		}
+	}
+	return nil
+}
+
 func (t *Topic) String() string {
 	return fmt.Sprintf("<Topic: %s - %s>", t.GetName(), t.Link)
 }
+
+// getOrganizeTopics 解析组织页面上 selector 里的话题标签
+func (t *Topic) getOrganizeTopics(ctx context.Context, selector string) ([]*Topic, error) {
+	doc, err := t.organize.DocCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// <a class="zm-item-tag" href="/topic/19552330" data-token="19552330" data-topicid="252330">编程语言</a>
+	var topics []*Topic
+	doc.Find(selector).Find("a.zm-item-tag").Each(func(index int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		topics = append(topics, newTopic(t.client, t.client.makeZhihuLink(href), strip(sel.Text())))
+	})
+	return topics, nil
+}
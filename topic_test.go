@@ -0,0 +1,88 @@
+package zhihu
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_Topic(t *testing.T) {
+	topic, err := newReplayClient().Topic("https://www.zhihu.com/topic/19552832", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := topic.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+
+	if name := topic.GetName(); name != "Python" {
+		t.Errorf("GetName() returns %s", name)
+	}
+	if desc := topic.GetDescription(); desc != "Python 是一种面向对象的解释型计算机程序设计语言，在设计中注重代码的可读性，同时也是一种功能强大的通用型语言。" {
+		t.Errorf("GetDescription() returns %s", desc)
+	}
+	if num := topic.GetFollowersNum(); num != 82155 {
+		t.Errorf("GetFollowersNum() returns %d", num)
+	}
+
+	authors := topic.GetTopAuthors()
+	if len(authors) != 2 {
+		t.Fatalf("GetTopAuthors() returns %d authors, want 2", len(authors))
+	}
+	if authors[1].GetUserID() != "灰蛙" || authors[1].GetBio() != "Python 爱好者" || authors[1].Link != "https://www.zhihu.com/people/hui-wa" {
+		t.Errorf("GetTopAuthors() returns error result: %s, bio %s", authors[1], authors[1].GetBio())
+	}
+}
+
+func Test_TopicTree(t *testing.T) {
+	topic, err := newReplayClient().Topic("https://www.zhihu.com/topic/19552832", "Python")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parents := topic.GetParents()
+	if len(parents) != 1 || parents[0].GetName() != "编程语言" || parents[0].Link != "https://www.zhihu.com/topic/19552330" {
+		t.Errorf("GetParents() returns error result: %v", parents)
+	}
+	children := topic.GetChildren()
+	if len(children) != 3 || children[0].GetName() != "Django" || children[2].Link != "https://www.zhihu.com/topic/19600426" {
+		t.Errorf("GetChildren() returns error result: %v", children)
+	}
+
+	// Scrapy 同时是 Python 和网络爬虫的子话题，只会访问一次
+	var names []string
+	var depths []int
+	err = WalkTopicTree(context.Background(), topic, 2, func(topic *Topic, depth int) error {
+		names = append(names, topic.GetName())
+		depths = append(depths, depth)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkTopicTree returns error: %s", err.Error())
+	}
+	expected := []string{"Python", "Django", "网络爬虫", "Scrapy", "Django REST framework"}
+	if len(names) != len(expected) {
+		t.Fatalf("WalkTopicTree visits %v, want %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("WalkTopicTree visits %v, want %v", names, expected)
+			break
+		}
+	}
+	if depths[0] != 0 || depths[3] != 1 || depths[4] != 2 {
+		t.Errorf("WalkTopicTree returns error depths: %v", depths)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = WalkTopicTree(context.Background(), topic, -1, func(topic *Topic, depth int) error {
+		if count++; count == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 2 {
+		t.Errorf("WalkTopicTree returns %v after %d topics, want stop after 2", err, count)
+	}
+}
@@ -0,0 +1,117 @@
+package zhihu
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	rePeoplePath     = regexp.MustCompile(`^/(people|org)/([^/]+)(?:/.*)?$`)
+	reAnswerPath     = regexp.MustCompile(`^/question/([0-9]+)/answer/([0-9]+)$`)
+	reQuestionPrefix = regexp.MustCompile(`^/question/([0-9]+)(?:/.*)?$`)
+	reCollectionID   = regexp.MustCompile(`^/collection/([0-9]+)$`)
+	reTopicPrefix    = regexp.MustCompile(`^/topic/([0-9]+)(?:/.*)?$`)
+)
+
+// zhihuHosts 是知乎自己的 host，这些链接会被转换成基于 Session.BaseURL 的链接
+var zhihuHosts = []string{"zhihu.com", "www.zhihu.com", "m.zhihu.com"}
+
+const (
+	columnHost   = "zhuanlan.zhihu.com"
+	redirectHost = "link.zhihu.com"
+)
+
+// ParseURL 识别一个知乎链接，返回对应的 *User, *Question, *Answer, *Collection, *Topic, *Column 或 *Article，
+// 可以用 type switch 判断类型。使用默认的 Client，见 Client.ParseURL
+func ParseURL(raw string) (interface{}, error) {
+	return defaultClient.ParseURL(raw)
+}
+
+// ParseURL 识别一个知乎链接，返回绑定在该 Client 上的 *User, *Question, *Answer, *Collection, *Topic, *Column 或 *Article。
+// 可以省略 http(s)://，支持 zhihu.com, m.zhihu.com 等 host、末尾的斜杠、查询参数，以及 link.zhihu.com 的跳转链接；
+// 用户和问题、话题的子页面（如 /people/jixin/answers）也会被识别为用户、问题、话题，机构账号（/org/xxx）保留原来的路径。
+// 不支持不带问题 ID 的回答短链接（/answer/42808045），和其他无法识别的链接一样返回 ErrInvalidURL；
+// 不是知乎链接时也返回 ErrInvalidURL
+func (c *Client) ParseURL(raw string) (interface{}, error) {
+	u, err := c.normalizeURL(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if path, ok := c.session.columnPath(u); ok {
+		// https://zhuanlan.zhihu.com/p/20761239, https://zhuanlan.zhihu.com/pythoner
+		if strings.HasPrefix(path, "/column/") {
+			path = strings.TrimPrefix(path, "/column")
+		}
+		switch {
+		case reArticleID.MatchString(path):
+			return c.Article(c.makeColumnLink(path), "")
+		case reColumnSlug.MatchString(path):
+			return c.Column(c.makeColumnLink(path), "")
+		}
+		return nil, wrapError(ErrInvalidURL, "无法识别的专栏链接：%s", raw)
+	}
+
+	path := u.Path
+	if m := reAnswerPath.FindStringSubmatch(path); m != nil {
+		question := newQuestion(c, c.makeZhihuLink("/question/"+m[1]), "")
+		return c.Answer(c.makeZhihuLink(path), question, nil)
+	}
+	if m := reQuestionPrefix.FindStringSubmatch(path); m != nil {
+		return c.Question(c.makeZhihuLink("/question/"+m[1]), "")
+	}
+	if m := rePeoplePath.FindStringSubmatch(path); m != nil {
+		return c.User(c.makeZhihuLink("/"+m[1]+"/"+m[2]), "")
+	}
+	if m := reCollectionID.FindStringSubmatch(path); m != nil {
+		return c.Collection(c.makeZhihuLink(path), "", nil)
+	}
+	if m := reTopicPrefix.FindStringSubmatch(path); m != nil {
+		return c.Topic(c.makeZhihuLink("/topic/"+m[1]), "")
+	}
+	return nil, wrapError(ErrInvalidURL, "无法识别的知乎链接：%s", raw)
+}
+
+// normalizeURL 解析 raw，展开 link.zhihu.com 的跳转，去掉查询参数和末尾的斜杠，
+// 并检查 host 是知乎或者 Session 接受的 host 之一
+func (c *Client) normalizeURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	for i := 0; i < 3; i++ {
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, wrapError(ErrInvalidURL, "不是 http(s) 链接：%s", raw)
+		}
+
+		if strings.EqualFold(u.Host, redirectHost) {
+			// https://link.zhihu.com/?target=https%3A//www.zhihu.com/question/28966220
+			raw = u.Query().Get("target")
+			if raw == "" {
+				return nil, wrapError(ErrInvalidURL, "跳转链接没有 target：%s", u)
+			}
+			continue
+		}
+
+		if _, ok := c.session.columnPath(u); !ok && !c.session.acceptHost(u.Host) && !isZhihuHost(u.Host) {
+			return nil, wrapError(ErrInvalidURL, "不是知乎链接：%s", raw)
+		}
+		u.RawQuery, u.Fragment = "", ""
+		if u.Path != "/" {
+			u.Path = strings.TrimRight(u.Path, "/")
+		}
+		return u, nil
+	}
+	return nil, wrapError(ErrInvalidURL, "跳转次数太多：%s", raw)
+}
+
+func isZhihuHost(host string) bool {
+	for _, h := range zhihuHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
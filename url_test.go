@@ -0,0 +1,85 @@
+package zhihu
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ParseURL(t *testing.T) {
+	ioMap := map[string]string{
+		"https://www.zhihu.com/people/jixin":                                          "user https://www.zhihu.com/people/jixin",
+		"http://zhihu.com/people/jixin/answers?page=2":                                "user https://www.zhihu.com/people/jixin",
+		"https://www.zhihu.com/org/zhi-hu-ri-bao-51-41/":                              "user https://www.zhihu.com/org/zhi-hu-ri-bao-51-41",
+		"www.zhihu.com/question/28966220":                                             "question https://www.zhihu.com/question/28966220",
+		"https://m.zhihu.com/question/4111472/?utm_source=wechat":                     "question https://www.zhihu.com/question/4111472",
+		"https://www.zhihu.com/question/28966220/followers":                           "question https://www.zhihu.com/question/28966220",
+		"https://www.zhihu.com/question/28966220/answer/42808045#comments":            "answer https://www.zhihu.com/question/28966220/answer/42808045",
+		"https://www.zhihu.com/collection/19653044/":                                  "collection https://www.zhihu.com/collection/19653044",
+		"https://www.zhihu.com/topic/19552832/hot":                                    "topic https://www.zhihu.com/topic/19552832",
+		"https://link.zhihu.com/?target=https%3A//www.zhihu.com/question/28966220":    "question https://www.zhihu.com/question/28966220",
+		"https://zhuanlan.zhihu.com/p/20761239?refer=pythoner":                        "article https://zhuanlan.zhihu.com/p/20761239",
+		"zhuanlan.zhihu.com/pythoner/":                                                "column https://zhuanlan.zhihu.com/pythoner",
+		" https://www.zhihu.com/question/28966220/answer/42808045?utm_medium=social ": "answer https://www.zhihu.com/question/28966220/answer/42808045",
+	}
+
+	client := NewClient(nil)
+	for raw, expected := range ioMap {
+		entity, err := client.ParseURL(raw)
+		if err != nil {
+			t.Errorf("ParseURL(%q) returns error: %v", raw, err)
+			continue
+		}
+
+		var got string
+		switch v := entity.(type) {
+		case *User:
+			got = "user " + v.Link
+		case *Question:
+			got = "question " + v.Link
+		case *Answer:
+			got = "answer " + v.Link
+			if v.GetQuestion().Link != "https://www.zhihu.com/question/28966220" {
+				t.Errorf("ParseURL(%q).GetQuestion() = %s", raw, v.GetQuestion().Link)
+			}
+		case *Collection:
+			got = "collection " + v.Link
+		case *Topic:
+			got = "topic " + v.Link
+		case *Column:
+			got = "column " + v.Link
+		case *Article:
+			got = "article " + v.Link
+		}
+		if got != expected {
+			t.Errorf("ParseURL(%q) = %s, expected %s", raw, got, expected)
+		}
+	}
+
+	errMap := map[string]error{
+		"https://zhuanlan.zhihu.com/":                    ErrInvalidURL,
+		"https://zhuanlan.zhihu.com/p/pythoner":          ErrInvalidURL,
+		"https://www.google.com/question/28966220":       ErrInvalidURL,
+		"ftp://www.zhihu.com/question/28966220":          ErrInvalidURL,
+		"https://www.zhihu.com/explore":                  ErrInvalidURL,
+		"https://www.zhihu.com/answer/42808045":          ErrInvalidURL,
+		"https://link.zhihu.com/?target=https%3A//a.com": ErrInvalidURL,
+	}
+	for raw, expected := range errMap {
+		if _, err := client.ParseURL(raw); !errors.Is(err, expected) {
+			t.Errorf("ParseURL(%q) returns %v, expected %v", raw, err, expected)
+		}
+	}
+}
+
+func Test_ParseURLBaseURL(t *testing.T) {
+	session := NewSession()
+	session.SetBaseURL("http://127.0.0.1:8080")
+	client := NewClient(session)
+
+	for _, raw := range []string{"https://www.zhihu.com/question/28966220", "http://127.0.0.1:8080/question/28966220/"} {
+		entity, err := client.ParseURL(raw)
+		if q, ok := entity.(*Question); !ok || err != nil || q.Link != "http://127.0.0.1:8080/question/28966220" {
+			t.Errorf("ParseURL(%q) = %v, %v", raw, entity, err)
+		}
+	}
+}
@@ -1,17 +1,19 @@
 package zhihu
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 var (
-	ANONYMOUS = NewUser("", "匿名用户")
+	ANONYMOUS = newUser(defaultClient, "", "匿名用户")
 )
 
 // User 表示一个知乎用户
@@ -24,32 +26,34 @@ type User struct {
 
 // NewUser 创建一个用户对象。
 // link 为空的时候表示匿名用户，此时 userId 仅允许 "匿名用户" 或 "知乎用户"；
-// userId 可以为空，这种情况下调用 GetUserID 会去解析用户主页
+// userId 可以为空，这种情况下调用 GetUserID 会去解析用户主页。
+// 参数不合法时会 panic。
+//
+// Deprecated: 请使用 Client.User（如 DefaultClient().User），参数不合法时返回 ErrInvalidURL 而不是 panic
 func NewUser(link string, userID string) *User {
-	if link == "" && !isAnonymous(userID) {
-		panic("调用 NewUser 的参数不合法")
+	user, err := defaultClient.User(link, userID)
+	if err != nil {
+		panic("调用 NewUser 的参数不合法：" + err.Error())
 	}
+	return user
+}
 
+func newUser(client *Client, link string, userID string) *User {
 	return &User{
-		Page:   newZhihuPage(link),
+		Page:   newZhihuPage(client, link),
 		userID: userID,
 	}
 }
 
 // GetUserID 返回用户的知乎 ID
 func (user *User) GetUserID() string {
-	if user.userID != "" {
-		return user.userID
-	}
-
-	doc := user.Doc()
-
-	// <div class="title-section ellipsis">
-	//   <span class="name">黄继新</span>，
-	//   <span class="bio" title="和知乎在一起">和知乎在一起</span>
-	// </div>
-	user.userID = strip(doc.Find("div.title-section.ellipsis").Find("span.name").Text())
-	return user.userID
+	return lazyField(user.Page, &user.userID, func() string {
+		// <div class="title-section ellipsis">
+		//   <span class="name">黄继新</span>，
+		//   <span class="bio" title="和知乎在一起">和知乎在一起</span>
+		// </div>
+		return strip(user.Doc().Find("div.title-section.ellipsis").Find("span.name").Text())
+	})
 }
 
 // GetDataID 返回用户的 data-id
@@ -82,28 +86,37 @@ func (user *User) GetDataID() string {
 		script := doc.Find(`script[data-name="ga_vars"]`).Text()
 		data := make(map[string]interface{})
 		json.Unmarshal([]byte(script), &data)
-		dataID = data["user_hash"].(string)
+		dataID, _ = data["user_hash"].(string)
 	}
 	user.setField("data-id", dataID)
 	return dataID
 }
 
-// GetBio 返回用户的 BIO
+// GetBio 返回用户的 BIO，出错时返回空字符串，需要区分“没有填写”和“请求失败”时请使用 GetBioCtx
 func (user *User) GetBio() string {
+	bio, _ := user.GetBioCtx(context.Background())
+	return bio
+}
+
+// GetBioCtx 同 GetBio，ctx 取消或超时后请求会被中止；页面载入失败时返回错误
+func (user *User) GetBioCtx(ctx context.Context) (string, error) {
 	if user.IsAnonymous() {
-		return ""
+		return "", nil
 	}
 
 	if got, ok := user.getStringField("bio"); ok {
-		return got
+		return got, nil
 	}
 
-	doc := user.Doc()
+	doc, err := user.DocCtx(ctx)
+	if err != nil {
+		return "", err
+	}
 
 	// <span class="bio" title="程序员，用 Python 和 Go 做服务端开发。">程序员，用 Python 和 Go 做服务端开发。</span>
 	bio := strip(doc.Find("span.bio").Eq(0).Text())
 	user.setField("bio", bio)
-	return bio
+	return bio, nil
 }
 
 // GetLocation 返回用户所在地
@@ -251,28 +264,40 @@ func (user *User) GetLogsNum() int {
 
 // GetFolloweesN 返回前 n 个用户关注的人，如果 n < 0，返回所有关注的人
 func (user *User) GetFolloweesN(n int) []*User {
-	users, err := user.getFolloweesOrFollowers("followees", n)
+	users, err := user.GetFolloweesNCtx(context.Background(), n)
 	if err != nil {
-		logger.Error("获取 %s 关注的人失败：%s", user.String(), err.Error())
-		return nil
+		user.client.logger.Error("获取关注的人失败", "user", user.Link, "err", err)
 	}
 	return users
 }
 
+// GetFolloweesNCtx 同 GetFolloweesN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetFolloweesNCtx(ctx context.Context, n int) ([]*User, error) {
+	return user.FolloweesIter(ctx).take(n)
+}
+
 // GetFollowees 返回用户关注的人
 func (user *User) GetFollowees() []*User {
 	return user.GetFolloweesN(-1)
 }
 
+// FolloweesIter 返回一个逐个获取用户关注的人的 Iterator
+func (user *User) FolloweesIter(ctx context.Context) *UserIterator {
+	return user.followeesOrFollowersIter(ctx, "followees")
+}
+
 // GetFollowersN 返回前 n 个粉丝，如果 n < 0，返回所有粉丝
 func (user *User) GetFollowersN(n int) []*User {
-	users, err := user.getFolloweesOrFollowers("followers", n)
+	users, err := user.GetFollowersNCtx(context.Background(), n)
 	if err != nil {
-		logger.Error("获取 %s 的粉丝失败：%s", user.String(), err.Error())
-		return nil
+		user.client.logger.Error("获取粉丝失败", "user", user.Link, "err", err)
 	}
 	return users
+}
 
+// GetFollowersNCtx 同 GetFollowersN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetFollowersNCtx(ctx context.Context, n int) ([]*User, error) {
+	return user.FollowersIter(ctx).take(n)
 }
 
 // GetFollowers 返回用户的粉丝列表
@@ -280,35 +305,40 @@ func (user *User) GetFollowers() []*User {
 	return user.GetFollowersN(-1)
 }
 
+// FollowersIter 返回一个逐个获取粉丝的 Iterator
+func (user *User) FollowersIter(ctx context.Context) *UserIterator {
+	return user.followeesOrFollowersIter(ctx, "followers")
+}
+
 // GetAsksN 返回用户前 n 个提问，如果 n < 0, 返回所有提问
 func (user *User) GetAsksN(n int) []*Question {
-	if user.IsAnonymous() {
-		return nil
-	}
+	questions, _ := user.GetAsksNCtx(context.Background(), n)
+	return questions
+}
 
-	total := user.GetAsksNum()
-	if n < 0 || n > total {
-		n = total
-	}
-	if n == 0 {
-		return nil
-	}
+// GetAsksNCtx 同 GetAsksN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetAsksNCtx(ctx context.Context, n int) ([]*Question, error) {
+	return user.AsksIter(ctx).take(n)
+}
 
-	page := 1
-	questions := make([]*Question, 0, n)
-	for page < ((n-1)/pageSize + 2) {
-		link := urlJoin(user.Link, fmt.Sprintf("/asks?page=%d", page))
-		doc, err := newDocumentFromURL(link)
-		if err != nil {
-			return nil
-		}
+// GetAsks 返回用户所有的提问
+func (user *User) GetAsks() []*Question {
+	return user.GetAsksN(-1)
+}
 
+// AsksIter 返回一个逐个获取用户提问的 Iterator
+func (user *User) AsksIter(ctx context.Context) *QuestionIterator {
+	if user.IsAnonymous() {
+		return emptyIterator[*Question]("user.asks", user.Link)
+	}
+	return newIterator(ctx, "user.asks", user.Link, profilePageFetcher(user, "/asks", user.GetAsksNum, func(doc *goquery.Document) []*Question {
+		var questions []*Question
 		doc.Find("div#zh-profile-ask-list").Children().Each(func(index int, sel *goquery.Selection) {
 			a := sel.Find("a.question_link")
 			title := strip(a.Text())
 			href, _ := a.Attr("href")
-			questionLink := makeZhihuLink(href)
-			thisQuestion := NewQuestion(questionLink, title)
+			questionLink := user.client.makeZhihuLink(href)
+			thisQuestion := newQuestion(user.client, questionLink, title)
 
 			// 获取回答数
 			answersNum := reMatchInt(strip(sel.Find("div.meta").Contents().Eq(4).Text()))
@@ -324,183 +354,349 @@ func (user *User) GetAsksN(n int) []*Question {
 
 			questions = append(questions, thisQuestion)
 		})
+		return questions
+	})).concurrent(user.client.session.concurrency)
+}
 
-		if n > 0 && len(questions) >= n {
-			return questions[:n]
-		}
+// GetAnswersN 返回用户前 n 个回答，如果 n < 0，返回所有回答
+func (user *User) GetAnswersN(n int) []*Answer {
+	answers, _ := user.GetAnswersNCtx(context.Background(), n)
+	return answers
+}
 
-		page++
-	}
-	return questions
+// GetAnswersNCtx 同 GetAnswersN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetAnswersNCtx(ctx context.Context, n int) ([]*Answer, error) {
+	return user.AnswersIter(ctx).take(n)
 }
 
-// GetAsks 返回用户所有的提问
-func (user *User) GetAsks() []*Question {
-	return user.GetAsksN(-1)
+// GetAnswers 返回用户所有的回答
+func (user *User) GetAnswers() []*Answer {
+	return user.GetAnswersN(-1)
 }
 
-// GetAnswersN 返回用户前 n 个回答，如果 n < 0，返回所有回答
-func (user *User) GetAnswersN(n int) []*Answer {
+// AnswersIter 返回一个逐个获取用户回答的 Iterator
+func (user *User) AnswersIter(ctx context.Context) *AnswerIterator {
 	if user.IsAnonymous() {
-		return nil
-	}
-
-	total := user.GetAnswersNum()
-	if n < 0 || n > total {
-		n = total
+		return emptyIterator[*Answer]("user.answers", user.Link)
 	}
-	if n == 0 {
-		return nil
-	}
-
-	page := 1
-	answers := make([]*Answer, 0, n)
-	for page < ((n-1)/pageSize + 2) {
-		link := urlJoin(user.Link, fmt.Sprintf("/answers?page=%d", page))
-		doc, err := newDocumentFromURL(link)
-		if err != nil {
-			return nil
-		}
-
+	return newIterator(ctx, "user.answers", user.Link, profilePageFetcher(user, "/answers", user.GetAnswersNum, func(doc *goquery.Document) []*Answer {
+		var answers []*Answer
 		doc.Find("div#zh-profile-answer-list").Children().Each(func(index int, sel *goquery.Selection) {
 			a := sel.Find("a.question_link")
 			qTitle := strip(a.Text())
 			answerHref, _ := a.Attr("href")
-			qLink := makeZhihuLink(answerHref[0:strings.Index(answerHref, "/answer")])
-			question := NewQuestion(qLink, qTitle)
-			thisAnswer := NewAnswer(makeZhihuLink(answerHref), question, user)
+			qLink := user.client.makeZhihuLink(answerHref[0:strings.Index(answerHref, "/answer")])
+			question := newQuestion(user.client, qLink, qTitle)
+			thisAnswer := newAnswer(user.client, user.client.makeZhihuLink(answerHref), question, user)
 
 			voteText, _ := sel.Find("a.zm-item-vote-count").Attr("data-votecount")
 			vote, _ := strconv.Atoi(voteText)
 			thisAnswer.setUpvote(vote)
+			thisAnswer.setTimes(sel, time.Now())
 
 			answers = append(answers, thisAnswer)
 		})
+		return answers
+	})).concurrent(user.client.session.concurrency)
+}
 
-		if n > 0 && len(answers) >= n {
-			return answers[:n]
-		}
-
-		page++
-	}
+// GetCollectionsN 返回用户前 n 个收藏夹，如果 n < 0，返回所有收藏夹
+func (user *User) GetCollectionsN(n int) []*Collection {
+	collections, _ := user.GetCollectionsNCtx(context.Background(), n)
+	return collections
+}
 
-	return answers
+// GetCollectionsNCtx 同 GetCollectionsN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetCollectionsNCtx(ctx context.Context, n int) ([]*Collection, error) {
+	return user.CollectionsIter(ctx).take(n)
 }
 
-// GetAnswers 返回用户所有的回答
-func (user *User) GetAnswers() []*Answer {
-	return user.GetAnswersN(-1)
+// GetCollections 返回用户的收藏夹
+func (user *User) GetCollections() []*Collection {
+	return user.GetCollectionsN(-1)
 }
 
-// GetCollectionsN 返回用户前 n 个收藏夹，如果 n < 0，返回所有收藏夹
-func (user *User) GetCollectionsN(n int) []*Collection {
+// CollectionsIter 返回一个逐个获取用户收藏夹的 Iterator
+func (user *User) CollectionsIter(ctx context.Context) *CollectionIterator {
 	if user.IsAnonymous() {
-		return nil
-	}
-
-	total := user.GetCollectionsNum()
-	if n < 0 || n > total {
-		n = total
-	}
-	if n == 0 {
-		return nil
+		return emptyIterator[*Collection]("user.collections", user.Link)
 	}
-
-	page := 1
-	collections := make([]*Collection, 0, n)
-	for page < ((n-1)/pageSize + 2) {
-		link := urlJoin(user.Link, fmt.Sprintf("/collections?page=%d", page))
-		doc, err := newDocumentFromURL(link)
-		if err != nil {
-			return nil
-		}
-
+	return newIterator(ctx, "user.collections", user.Link, profilePageFetcher(user, "/collections", user.GetCollectionsNum, func(doc *goquery.Document) []*Collection {
+		var collections []*Collection
 		doc.Find("div.zm-profile-section-item").Each(func(index int, sel *goquery.Selection) {
 			a := sel.Find("a.zm-profile-fav-item-title")
 			cName := strip(a.Text())
 			href, _ := a.Attr("href")
-			cLink := makeZhihuLink(href)
-			thisCollection := NewCollection(cLink, cName, user)
+			cLink := user.client.makeZhihuLink(href)
+			thisCollection := newCollection(user.client, cLink, cName, user)
 			collections = append(collections, thisCollection)
 		})
+		return collections
+	})).concurrent(user.client.session.concurrency)
+}
 
-		if n > 0 && len(collections) >= n {
-			return collections[:n]
+// profilePageFetcher 返回获取个人主页上 /asks, /answers 等分页列表的 pageFetcher，
+// 每页 20 个，total 返回总数，用于计算页数
+func profilePageFetcher[T any](user *User, tab string, total func() int, parse func(*goquery.Document) []T) pageFetcher[T] {
+	return func(ctx context.Context, cursor *Cursor) ([]T, bool, error) {
+		if _, err := user.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
+		totalPages := (total() + pageSize - 1) / pageSize
+		if cursor.Page >= totalPages {
+			return nil, false, nil
 		}
 
-		page++
+		link := urlJoin(user.Link, fmt.Sprintf("%s?page=%d", tab, cursor.Page+1))
+		doc, err := user.client.newDocumentFromURL(ctx, link)
+		if err != nil {
+			return nil, false, err
+		}
+		return parse(doc), cursor.Page+1 < totalPages, nil
 	}
+}
 
-	return collections
+// GetFollowedTopicsN 返回用户前 n 个关注的话题，如果 n < 0，返回所有话题
+func (user *User) GetFollowedTopicsN(n int) []*Topic {
+	topics, _ := user.GetFollowedTopicsNCtx(context.Background(), n)
+	return topics
 }
 
-// GetCollections 返回用户的收藏夹
-func (user *User) GetCollections() []*Collection {
-	return user.GetCollectionsN(-1)
+// GetFollowedTopicsNCtx 同 GetFollowedTopicsN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetFollowedTopicsNCtx(ctx context.Context, n int) ([]*Topic, error) {
+	return user.FollowedTopicsIter(ctx).take(n)
 }
 
-// GetFollowedTopicsN 返回用户前 n 个关注的话题，如果 n < 0，返回所有话题
-func (user *User) GetFollowedTopicsN(n int) []*Topic {
-	if user.IsAnonymous() {
-		return nil
-	}
+// GetFollowedTopics 返回用户关注的话题
+func (user *User) GetFollowedTopics() []*Topic {
+	return user.GetFollowedTopicsN(-1)
+}
 
-	total := user.GetFollowedTopicsNum()
-	if n < 0 || n > total {
-		n = total
-	}
-	if n == 0 {
-		return nil
+// FollowedTopicsIter 返回一个逐个获取用户关注的话题的 Iterator
+func (user *User) FollowedTopicsIter(ctx context.Context) *TopicIterator {
+	if user.IsAnonymous() {
+		return emptyIterator[*Topic]("user.topics", user.Link)
 	}
 
-	var (
-		link       = urlJoin(user.Link, "/topics")
-		gotDataNum = pageSize
-		offset     = 0
-		topics     = make([]*Topic, 0, n)
-	)
-
-	form := url.Values{}
-	form.Set("_xsrf", user.GetXSRF())
-	form.Set("start", "0")
+	return newIterator(ctx, "user.topics", user.Link, func(ctx context.Context, cursor *Cursor) ([]*Topic, bool, error) {
+		if _, err := user.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
+		if user.GetFollowedTopicsNum() == 0 {
+			return nil, false, nil
+		}
 
-	for gotDataNum == pageSize {
-		form.Set("offset", strconv.Itoa(offset))
-		doc, dataNum, err := newDocByNormalAjax(link, form)
+		form := url.Values{}
+		form.Set("_xsrf", user.GetXSRF())
+		form.Set("start", "0")
+		form.Set("offset", strconv.Itoa(cursor.Offset))
+		doc, dataNum, err := user.client.newDocByNormalAjax(ctx, urlJoin(user.Link, "/topics"), form)
 		if err != nil {
-			return nil
+			return nil, false, err
 		}
 
+		var topics []*Topic
 		doc.Find("div.zm-profile-section-item").Each(func(index int, sel *goquery.Selection) {
 			tName := strip(sel.Find("strong").Text())
 			tHref, _ := sel.Find("a.zm-list-avatar-link").Attr("href")
-			thisTopic := NewTopic(makeZhihuLink(tHref), tName)
+			thisTopic := newTopic(user.client, user.client.makeZhihuLink(tHref), tName)
 			topics = append(topics, thisTopic)
 		})
 
-		if n > 0 && len(topics) >= n {
-			return topics[:n]
+		return topics, dataNum == pageSize, nil
+	})
+}
+
+// GetPostsN 返回用户最新的 n 篇专栏文章，如果 n < 0，返回所有文章
+func (user *User) GetPostsN(n int) []*Article {
+	articles, _ := user.GetPostsNCtx(context.Background(), n)
+	return articles
+}
+
+// GetPostsNCtx 同 GetPostsN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetPostsNCtx(ctx context.Context, n int) ([]*Article, error) {
+	return user.PostsIter(ctx).take(n)
+}
+
+// GetPosts 返回用户所有的专栏文章
+func (user *User) GetPosts() []*Article {
+	return user.GetPostsN(-1)
+}
+
+// PostsIter 返回一个逐个获取用户专栏文章的 Iterator
+func (user *User) PostsIter(ctx context.Context) *ArticleIterator {
+	if user.IsAnonymous() {
+		return emptyIterator[*Article]("user.posts", user.Link)
+	}
+	return newIterator(ctx, "user.posts", user.Link, profilePageFetcher(user, "/posts", user.GetPostsNum, func(doc *goquery.Document) []*Article {
+		var articles []*Article
+		doc.Find("div.zm-profile-section-item").Each(func(index int, sel *goquery.Selection) {
+			a := sel.Find("a.post-link")
+			href, _ := a.Attr("href")
+			article, err := user.client.Article(user.client.columnLinkFromHref(href), strip(a.Text()))
+			if err != nil {
+				user.client.logger.Warn("无法识别的专栏文章链接", "user", user.Link, "href", href)
+				return
+			}
+			articles = append(articles, article)
+		})
+		return articles
+	})).concurrent(user.client.session.concurrency)
+}
+
+// GetFollowedColumnsN 返回用户前 n 个关注的专栏，如果 n < 0，返回所有专栏
+func (user *User) GetFollowedColumnsN(n int) []*Column {
+	columns, _ := user.GetFollowedColumnsNCtx(context.Background(), n)
+	return columns
+}
+
+// GetFollowedColumnsNCtx 同 GetFollowedColumnsN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetFollowedColumnsNCtx(ctx context.Context, n int) ([]*Column, error) {
+	return user.FollowedColumnsIter(ctx).take(n)
+}
+
+// GetFollowedColumns 返回用户关注的专栏
+func (user *User) GetFollowedColumns() []*Column {
+	return user.GetFollowedColumnsN(-1)
+}
+
+// FollowedColumnsIter 返回一个逐个获取用户关注的专栏的 Iterator
+func (user *User) FollowedColumnsIter(ctx context.Context) *ColumnIterator {
+	if user.IsAnonymous() {
+		return emptyIterator[*Column]("user.columns", user.Link)
+	}
+
+	return newIterator(ctx, "user.columns", user.Link, func(ctx context.Context, cursor *Cursor) ([]*Column, bool, error) {
+		if _, err := user.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
+		if user.GetFollowedColumnsNum() == 0 {
+			return nil, false, nil
 		}
 
-		gotDataNum = dataNum
-		offset += gotDataNum
+		form := url.Values{}
+		form.Set("_xsrf", user.GetXSRF())
+		form.Set("start", "0")
+		form.Set("offset", strconv.Itoa(cursor.Offset))
+		doc, dataNum, err := user.client.newDocByNormalAjax(ctx, urlJoin(user.Link, "/columns/followed"), form)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var columns []*Column
+		doc.Find("div.zm-profile-section-item").Each(func(index int, sel *goquery.Selection) {
+			name := strip(sel.Find("strong").Text())
+			href, _ := sel.Find("a.zm-list-avatar-link").Attr("href")
+			column, err := user.client.Column(user.client.columnLinkFromHref(href), name)
+			if err != nil {
+				user.client.logger.Warn("无法识别的专栏链接", "user", user.Link, "href", href)
+				return
+			}
+			columns = append(columns, column)
+		})
+
+		return columns, dataNum == pageSize, nil
+	})
+}
+
+// GetActivitiesN 返回用户最近的 n 条动态，如果 n < 0，返回所有动态
+func (user *User) GetActivitiesN(n int) []*Activity {
+	activities, _ := user.GetActivitiesNCtx(context.Background(), n)
+	return activities
+}
+
+// GetActivitiesNCtx 同 GetActivitiesN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetActivitiesNCtx(ctx context.Context, n int) ([]*Activity, error) {
+	return user.ActivitiesIter(ctx).take(n)
+}
+
+// GetActivities 返回用户所有的动态，最近的在前面
+func (user *User) GetActivities() []*Activity {
+	return user.GetActivitiesN(-1)
+}
+
+// ActivitiesIter 返回一个逐个获取用户动态的 Iterator，最近的在前面。
+// 只返回提问、回答、赞同回答、关注问题、话题、收藏夹、专栏和发表文章这几种动态，其他的会被忽略
+func (user *User) ActivitiesIter(ctx context.Context) *ActivityIterator {
+	if user.IsAnonymous() {
+		return emptyIterator[*Activity]("user.activities", user.Link)
 	}
 
-	return topics
+	return newIterator(ctx, "user.activities", user.Link, func(ctx context.Context, cursor *Cursor) ([]*Activity, bool, error) {
+		items, more, err := user.activitiesPage(ctx, cursor, time.Time{})
+		if err != nil {
+			return nil, false, err
+		}
+
+		var activities []*Activity
+		items.Each(func(index int, sel *goquery.Selection) {
+			if activity := user.client.newActivity(sel); activity != nil {
+				activities = append(activities, activity)
+			}
+		})
+		return activities, more, nil
+	})
 }
 
-// GetFollowedTopics 返回用户关注的话题
-func (user *User) GetFollowedTopics() []*Topic {
-	return user.GetFollowedTopicsN(-1)
+// GetLikesN 返回用户最近赞同的 n 个回答，如果 n < 0，返回所有赞同过的回答
+func (user *User) GetLikesN(n int) []*Answer {
+	answers, _ := user.GetLikesNCtx(context.Background(), n)
+	return answers
+}
+
+// GetLikesNCtx 同 GetLikesN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetLikesNCtx(ctx context.Context, n int) ([]*Answer, error) {
+	return user.LikesIter(ctx, time.Time{}).take(n)
 }
 
-// GetLikes 返回用户赞过的回答
+// GetLikes 返回用户赞同过的回答，最近的在前面
 func (user *User) GetLikes() []*Answer {
+	return user.GetLikesN(-1)
+}
+
+// GetLikesSince 返回用户在 since 之后（含）赞同的回答，最近的在前面，遇到更早的动态时停止翻页
+func (user *User) GetLikesSince(since time.Time) []*Answer {
+	answers, _ := user.GetLikesSinceCtx(context.Background(), since)
+	return answers
+}
+
+// GetLikesSinceCtx 同 GetLikesSince，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetLikesSinceCtx(ctx context.Context, since time.Time) ([]*Answer, error) {
+	return user.GetLikesSinceNCtx(ctx, since, -1)
+}
+
+// GetLikesSinceN 返回用户在 since 之后（含）赞同的最近 n 个回答，如果 n < 0，同 GetLikesSince
+func (user *User) GetLikesSinceN(since time.Time, n int) []*Answer {
+	answers, _ := user.GetLikesSinceNCtx(context.Background(), since, n)
+	return answers
+}
+
+// GetLikesSinceNCtx 同 GetLikesSinceN，ctx 取消或超时后停止翻页并返回错误
+func (user *User) GetLikesSinceNCtx(ctx context.Context, since time.Time, n int) ([]*Answer, error) {
+	return user.LikesIter(ctx, since).take(n)
+}
+
+// LikesIter 返回一个逐个获取用户赞同的回答的 Iterator，数据来自用户的动态，最近的在前面。
+// since 不为零值时只返回 since 之后（含）赞同的回答，遇到更早的动态时停止翻页
+func (user *User) LikesIter(ctx context.Context, since time.Time) *AnswerIterator {
 	if user.IsAnonymous() {
-		return nil
+		return emptyIterator[*Answer]("user.likes", user.Link)
 	}
-	// TODO
-	return nil
+
+	return newIterator(ctx, "user.likes", user.Link, func(ctx context.Context, cursor *Cursor) ([]*Answer, bool, error) {
+		items, more, err := user.activitiesPage(ctx, cursor, since)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var answers []*Answer
+		items.Each(func(index int, sel *goquery.Selection) {
+			if activityTypes[activityDetail(sel)] == ActivityUpvoteAnswer {
+				answers = append(answers, user.client.newAnswerFromActivity(sel))
+			}
+		})
+		return answers, more, nil
+	})
 }
 
 // GetVotedAnswers 是 GetLikes 的别名
@@ -510,14 +706,16 @@ func (user *User) GetVotedAnswers() []*Answer {
 
 // IsAnonymous 表示该用户是否匿名用户
 func (user *User) IsAnonymous() bool {
+	user.mu.RLock()
+	defer user.mu.RUnlock()
 	return isAnonymous(user.userID)
 }
 
 func (user *User) String() string {
 	if user.IsAnonymous() {
-		return fmt.Sprintf("<User: %s>", user.userID)
+		return fmt.Sprintf("<User: %s>", user.GetUserID())
 	}
-	return fmt.Sprintf("<User: %s - %s>", user.userID, user.Link)
+	return fmt.Sprintf("<User: %s - %s>", user.GetUserID(), user.Link)
 }
 
 func (user *User) getProfile(cacheKey string) string {
@@ -678,75 +876,57 @@ func (user *User) getProfileNum(cacheKey string) int {
 	return num
 }
 
-func (user *User) getFolloweesOrFollowers(eeOrEr string, limit int) ([]*User, error) {
+// followeesOrFollowersIter 返回逐个获取关注的人（eeOrEr 为 followees）或粉丝（followers）的 Iterator
+func (user *User) followeesOrFollowersIter(ctx context.Context, eeOrEr string) *UserIterator {
 	if user.IsAnonymous() {
-		return nil, nil
-	}
-
-	if limit == 0 {
-		return nil, nil
+		return emptyIterator[*User]("user."+eeOrEr, user.Link)
 	}
 
-	var (
-		referer, ajaxURL string
-		offset, totalNum int
-		hashID           = user.GetDataID()
-	)
-
-	if eeOrEr == "followees" {
-		referer = urlJoin(user.Link, "/followees")
-		ajaxURL = makeZhihuLink("/node/ProfileFolloweesListV2")
-		totalNum = user.GetFollowersNum()
-	} else {
-		referer = urlJoin(user.Link, "/followers")
-		ajaxURL = makeZhihuLink("/node/ProfileFollowersListV2")
-		totalNum = user.GetFolloweesNum()
-	}
-
-	if limit < 0 || limit > totalNum {
-		limit = totalNum
+	referer := urlJoin(user.Link, "/"+eeOrEr)
+	ajaxURL := user.client.makeZhihuLink("/node/ProfileFolloweesListV2")
+	if eeOrEr == "followers" {
+		ajaxURL = user.client.makeZhihuLink("/node/ProfileFollowersListV2")
 	}
 
-	form := url.Values{}
-	form.Set("_xsrf", user.GetXSRF())
-	form.Set("method", "next")
+	return newIterator(ctx, "user."+eeOrEr, user.Link, func(ctx context.Context, cursor *Cursor) ([]*User, bool, error) {
+		if _, err := user.DocCtx(ctx); err != nil {
+			return nil, false, err
+		}
 
-	users := make([]*User, 0, limit)
-	for {
-		form.Set("params", fmt.Sprintf(`{"offset":%d,"order_by":"created","hash_id":"%s"}`, offset, hashID))
-		body := strings.NewReader(form.Encode())
-		resp, err := gSession.Ajax(ajaxURL, body, referer)
-		if err != nil {
-			return nil, err
+		totalNum := user.GetFolloweesNum()
+		if eeOrEr == "followers" {
+			totalNum = user.GetFollowersNum()
+		}
+		if cursor.Offset >= totalNum {
+			return nil, false, nil
+		}
+		if cursor.HashID == "" {
+			cursor.HashID = user.GetDataID()
 		}
 
-		defer resp.Body.Close()
+		form := url.Values{}
+		form.Set("_xsrf", user.GetXSRF())
+		form.Set("method", "next")
+		form.Set("params", fmt.Sprintf(`{"offset":%d,"order_by":"created","hash_id":"%s"}`, cursor.Offset, cursor.HashID))
 		result := nodeListResult{}
-		err = json.NewDecoder(resp.Body).Decode(&result)
-		if err != nil {
-			logger.Error("json decode failed: %s", err.Error())
-			return nil, err
+		if err := user.client.ajaxJSON(ctx, ajaxURL, form, referer, &result); err != nil {
+			user.client.logger.Error("获取 "+eeOrEr+" 失败", "user", user.Link, "err", err)
+			return nil, false, err
 		}
 
+		users := make([]*User, 0, len(result.Msg))
 		for _, userHTML := range result.Msg {
-			thisUser, err := newUserFromHTML(userHTML)
+			thisUser, err := user.client.newUserFromHTML(userHTML)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			users = append(users, thisUser)
-			if len(users) == limit {
-				break
-			}
 		}
 
-		// 已经获取了需要的数量，或者数量不够，但是已经到了最后一页
-		if len(users) == limit || len(result.Msg) < pageSize {
-			break
-		} else {
-			offset += pageSize
-		}
-	}
-	return users, nil
+		// 数量不够一页，说明已经到了最后一页
+		more := len(result.Msg) == pageSize && cursor.Offset+pageSize < totalNum
+		return users, more, nil
+	})
 }
 
 func (user *User) setFollowersNum(value int) {
@@ -773,17 +953,17 @@ func isAnonymous(userID string) bool {
 	return userID == "匿名用户" || userID == "知乎用户"
 }
 
-func newUserFromHTML(html string) (*User, error) {
+func (c *Client) newUserFromHTML(html string) (*User, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		logger.Error("NewDocumentFromReader failed: %s", err.Error())
+		c.logger.Error("解析用户卡片失败", "err", err)
 		return nil, err
 	}
 
-	return newUserFromSelector(doc.Selection), nil
+	return c.newUserFromSelector(doc.Selection), nil
 }
 
-func newUserFromSelector(sel *goquery.Selection) *User {
+func (c *Client) newUserFromSelector(sel *goquery.Selection) *User {
 	a := sel.Find("h2.zm-list-content-title").Find("a.zg-link")
 	if a.Size() == 0 {
 		// 匿名用户，没有用户主页入口
@@ -793,7 +973,7 @@ func newUserFromSelector(sel *goquery.Selection) *User {
 	userId := strip(a.Text())
 	link, _ := a.Attr("href")
 
-	user := NewUser(link, userId)
+	user := newUser(c, link, userId)
 
 	// 获取 BIO
 	bio := strip(sel.Find("div.zg-big-gray").Text())
@@ -0,0 +1,232 @@
+package zhihu
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newReplayUser(t *testing.T) *User {
+	user, err := newReplayClient().User("https://www.zhihu.com/people/jixin", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := user.Refresh(); err != nil {
+		t.Fatalf("Refresh returns error: %s", err.Error())
+	}
+	return user
+}
+
+func Test_UserProfile(t *testing.T) {
+	user := newReplayUser(t)
+
+	ioMap := map[string][2]string{
+		"GetUserID":    {user.GetUserID(), "黄继新"},
+		"GetDataID":    {user.GetDataID(), "b6f80220378c8b0b78175dd6a0b9c680"},
+		"GetBio":       {user.GetBio(), "和知乎在一起"},
+		"GetLocation":  {user.GetLocation(), "北京"},
+		"GetBusiness":  {user.GetBusiness(), "互联网"},
+		"GetEducation": {user.GetEducation(), "中国传媒大学"},
+		"GetGender":    {user.GetGender(), "male"},
+		"GetAvatar":    {user.GetAvatar(), "https://pic1.zhimg.com/3a6c25ac3864540e80cdef9bc2a73900_l.jpg"},
+		"GetWeiboURL":  {user.GetWeiboURL(), "http://weibo.com/jixin"},
+	}
+	for method, value := range ioMap {
+		if value[0] != value[1] {
+			t.Errorf("%s() returns %s, want %s", method, value[0], value[1])
+		}
+	}
+
+	if avatar := user.GetAvatarWithSize("hd"); avatar != "https://pic1.zhimg.com/3a6c25ac3864540e80cdef9bc2a73900_hd.jpg" {
+		t.Errorf("GetAvatarWithSize(hd) returns %s", avatar)
+	}
+}
+
+func Test_UserNums(t *testing.T) {
+	user := newReplayUser(t)
+
+	ioMap := map[string][2]int{
+		"GetFolloweesNum":       {user.GetFolloweesNum(), 9190},
+		"GetFollowersNum":       {user.GetFollowersNum(), 754769},
+		"GetFollowedColumnsNum": {user.GetFollowedColumnsNum(), 12},
+		"GetFollowedTopicsNum":  {user.GetFollowedTopicsNum(), 157},
+		"GetAgreeNum":           {user.GetAgreeNum(), 68200},
+		"GetThanksNum":          {user.GetThanksNum(), 17511},
+		"GetAsksNum":            {user.GetAsksNum(), 1336},
+		"GetAnswersNum":         {user.GetAnswersNum(), 785},
+		"GetPostsNum":           {user.GetPostsNum(), 91},
+		"GetCollectionsNum":     {user.GetCollectionsNum(), 44},
+		"GetLogsNum":            {user.GetLogsNum(), 51471},
+	}
+	for method, value := range ioMap {
+		if value[0] != value[1] {
+			t.Errorf("%s() returns %d, want %d", method, value[0], value[1])
+		}
+	}
+}
+
+func Test_UserGetAsks(t *testing.T) {
+	questions := newReplayUser(t).GetAsksN(2)
+	if len(questions) != 2 {
+		t.Fatalf("GetAsksN(2) returns %d questions, want 2", len(questions))
+	}
+
+	q := questions[0]
+	if q.GetTitle() != "知乎上有哪些让人拍案叫绝的回答？" || q.Link != "https://www.zhihu.com/question/19606436" {
+		t.Errorf("GetAsksN returns error result: %s", q)
+	}
+	if q.GetAnswersNum() != 2311 || q.GetFollowersNum() != 51233 || q.GetVisitTimes() != 3870 {
+		t.Errorf("GetAsksN returns error nums: answers %d, followers %d, visits %d",
+			q.GetAnswersNum(), q.GetFollowersNum(), q.GetVisitTimes())
+	}
+}
+
+func Test_UserGetAnswers(t *testing.T) {
+	user := newReplayUser(t)
+	answers := user.GetAnswersN(2)
+	if len(answers) != 2 {
+		t.Fatalf("GetAnswersN(2) returns %d answers, want 2", len(answers))
+	}
+
+	a := answers[1]
+	if a.Link != "https://www.zhihu.com/question/20021735/answer/13498472" || a.GetUpvote() != 4321 || a.GetAuthor() != user {
+		t.Errorf("GetAnswersN returns error result: %s, upvote %d", a.Link, a.GetUpvote())
+	}
+	if q := a.GetQuestion(); q.GetTitle() != "知乎的产品设计有哪些细节？" || q.Link != "https://www.zhihu.com/question/20021735" {
+		t.Errorf("GetAnswersN returns error question: %s", q)
+	}
+}
+
+func Test_UserGetCollections(t *testing.T) {
+	collections := newReplayUser(t).GetCollectionsN(2)
+	if len(collections) != 2 {
+		t.Fatalf("GetCollectionsN(2) returns %d collections, want 2", len(collections))
+	}
+	if c := collections[1]; c.GetName() != "产品设计" || c.Link != "https://www.zhihu.com/collection/19573315" {
+		t.Errorf("GetCollectionsN returns error result: %s", c)
+	}
+}
+
+func Test_UserGetPosts(t *testing.T) {
+	articles := newReplayUser(t).GetPostsN(2)
+	if len(articles) != 2 {
+		t.Fatalf("GetPostsN(2) returns %d articles, want 2", len(articles))
+	}
+	if a := articles[1]; a.GetTitle() != "我们为什么做知乎日报" || a.GetID() != 20698765 || a.Link != "https://zhuanlan.zhihu.com/p/20698765" {
+		t.Errorf("GetPostsN returns error result: %s", a)
+	}
+}
+
+func Test_UserGetFollowedColumns(t *testing.T) {
+	columns := newReplayUser(t).GetFollowedColumnsN(2)
+	if len(columns) != 2 {
+		t.Fatalf("GetFollowedColumnsN(2) returns %d columns, want 2", len(columns))
+	}
+	if c := columns[0]; c.GetName() != "Python 程序员" || c.GetSlug() != "pythoner" || c.Link != "https://zhuanlan.zhihu.com/pythoner" {
+		t.Errorf("GetFollowedColumnsN returns error result: %s", c)
+	}
+}
+
+func Test_UserGetLikes(t *testing.T) {
+	user := newReplayUser(t)
+	likes := user.GetLikes()
+	if len(likes) != 7 {
+		t.Fatalf("GetLikes() returns %d answers, want 7", len(likes))
+	}
+
+	a := likes[0]
+	if a.Link != "https://www.zhihu.com/question/41171543/answer/88475539" || a.GetUpvote() != 8000 {
+		t.Errorf("GetLikes() returns error result: %s, upvote %d", a.Link, a.GetUpvote())
+	}
+	if author := a.GetAuthor(); author.GetUserID() != "柯森杰" || author.Link != "https://www.zhihu.com/people/kesenjie" {
+		t.Errorf("GetLikes() returns error author: %s", author)
+	}
+	if q := a.GetQuestion(); q.GetTitle() != "如何评价第一局比赛 AlphaGo 战胜李世石？" || q.Link != "https://www.zhihu.com/question/41171543" {
+		t.Errorf("GetLikes() returns error question: %s", q)
+	}
+	if a := likes[6]; a.Link != "https://www.zhihu.com/question/41171543/answer/88475593" {
+		t.Errorf("likes[6] returns error result: %s", a.Link)
+	}
+
+	since := time.Unix(1460000000-11*3600, 0)
+	recent := user.GetLikesSince(since)
+	if len(recent) != 3 || recent[2].Link != "https://www.zhihu.com/question/41171543/answer/88475550" {
+		t.Fatalf("GetLikesSince() returns %v", recent)
+	}
+	if got := user.GetLikesSinceN(since, 2); len(got) != 2 || got[1].Link != recent[1].Link {
+		t.Errorf("GetLikesSinceN() returns %v", got)
+	}
+
+	// 动态按时间翻页，从 Cursor 继续时应该请求第二页
+	it := user.LikesIter(context.Background(), time.Time{})
+	for i := 0; i < 6 && it.Next(); i++ {
+	}
+	resumed := newReplayUser(t).LikesIter(context.Background(), time.Time{})
+	if err := resumed.Seek(it.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.Next() || resumed.Value().Link != likes[6].Link || resumed.Next() {
+		t.Errorf("resumed LikesIter returns error result, err: %v", resumed.Err())
+	}
+}
+
+func Test_UserGetActivities(t *testing.T) {
+	activities := newReplayUser(t).GetActivities()
+	if len(activities) != 25 {
+		t.Fatalf("GetActivities() returns %d activities, want 25", len(activities))
+	}
+
+	types := []ActivityType{
+		ActivityUpvoteAnswer, ActivityAnswerQuestion, ActivityAskQuestion, ActivityFollowQuestion,
+		ActivityFollowTopic, ActivityFollowCollection, ActivityPublishArticle, ActivityFollowColumn,
+	}
+	for i, expected := range types {
+		if activities[i].Type != expected {
+			t.Errorf("activities[%d].Type = %s, want %s", i, activities[i].Type, expected)
+		}
+	}
+	if first := activities[0]; !first.Time.Equal(time.Unix(1460000000, 0)) || first.Answer().GetAuthor().GetUserID() != "柯森杰" {
+		t.Errorf("activities[0] returns error result: %s", first)
+	}
+	if a := activities[1].Answer(); a == nil || a.Link != "https://www.zhihu.com/question/20021735/answer/13498473" || a.GetAuthor().GetUserID() != "黄继新" {
+		t.Errorf("activities[1].Answer() returns error result: %v", a)
+	}
+	if q := activities[2].Question(); q == nil || q.Link != "https://www.zhihu.com/question/19606438" || activities[2].Answer() != nil {
+		t.Errorf("activities[2].Question() returns error result: %v", q)
+	}
+	if topic := activities[4].Topic(); topic == nil || topic.GetName() != "Python" {
+		t.Errorf("activities[4].Topic() returns error result: %v", topic)
+	}
+	if c := activities[5].Collection(); c == nil || c.Link != "https://www.zhihu.com/collection/19573315" || c.GetName() != "产品设计" {
+		t.Errorf("activities[5].Collection() returns error result: %v", c)
+	}
+	if a := activities[6].Article(); a == nil || a.GetID() != 20761239 || a.GetTitle() != "用 Python 写一个知乎爬虫" {
+		t.Errorf("activities[6].Article() returns error result: %v", a)
+	}
+	if c := activities[7].Column(); c == nil || c.GetSlug() != "pythoner" {
+		t.Errorf("activities[7].Column() returns error result: %v", c)
+	}
+	if last := activities[24]; last.Type != ActivityUpvoteAnswer || !last.Time.Equal(time.Unix(1459913600, 0)) {
+		t.Errorf("activities[24] returns error result: %s", last)
+	}
+}
+
+func Test_UserGetFollowedTopics(t *testing.T) {
+	topics := newReplayUser(t).GetFollowedTopicsN(2)
+	if len(topics) != 2 {
+		t.Fatalf("GetFollowedTopicsN(2) returns %d topics, want 2", len(topics))
+	}
+	if topic := topics[0]; topic.GetName() != "Python" || topic.Link != "https://www.zhihu.com/topic/19552832" {
+		t.Errorf("GetFollowedTopicsN returns error result: %s", topic)
+	}
+}
+
+func Test_UserGetFollowees(t *testing.T) {
+	followees := newReplayUser(t).GetFolloweesN(2)
+	if len(followees) != 2 {
+		t.Fatalf("GetFolloweesN(2) returns %d users, want 2", len(followees))
+	}
+	if user := followees[1]; user.GetUserID() != "周源" || user.GetAnswersNum() != 112 {
+		t.Errorf("GetFolloweesN returns error result: %s, answers %d", user, user.GetAnswersNum())
+	}
+}
@@ -1,47 +1,69 @@
 package zhihu
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"net/url"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/fatih/color"
+	"golang.org/x/net/html"
 )
 
 const (
-	userAgent    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/48.0.2564.116 Safari/537.36"
-	baseZhihuURL = "https://www.zhihu.com"
-	pageSize     = 20
+	userAgent     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/48.0.2564.116 Safari/537.36"
+	baseZhihuURL  = "https://www.zhihu.com"
+	baseColumnURL = "https://zhuanlan.zhihu.com"
+	pageSize      = 20
 )
 
 var (
-	reQuestionURL    = regexp.MustCompile("^(http|https)://www.zhihu.com/question/[0-9]{8}$")
-	reCollectionURL  = regexp.MustCompile("^(http|https)://www.zhihu.com/collection/[0-9]{8,9}$") // bugfix: for private collection
-	reTopicURL       = regexp.MustCompile("^(http|https)://www.zhihu.com/topic/[0-9]{8}$")
-	reGetNumber      = regexp.MustCompile(`([0-9])+`)
-	reAvatarReplacer = regexp.MustCompile(`_(s|xs|m|l|xl|hd).(png|jpg)`)
-	reIsEmail        = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
-	logger           = Logger{Enabled: true}
+	reQuestionPath          = regexp.MustCompile("^/question/[0-9]+$")
+	reCollectionPath        = regexp.MustCompile("^/collection/[0-9]+$")
+	reTopicPath             = regexp.MustCompile("^/topic/[0-9]+$")
+	reGetNumber             = regexp.MustCompile(`([0-9])+`)
+	reAvatarReplacer        = regexp.MustCompile(`_(s|xs|m|l|xl|hd).(png|jpg)`)
+	reIsEmail               = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
+	logger           Logger = NewTextLogger(nil)
 )
 
-func validQuestionURL(value string) bool {
-	return reQuestionURL.MatchString(value)
+var (
+	reTimeDate    = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2})(?:\s+([0-9]{1,2}:[0-9]{2}))?$`)
+	reTimeClock   = regexp.MustCompile(`^(昨天)?\s*([0-9]{1,2}):([0-9]{2})$`)
+	reTimeElapsed = regexp.MustCompile(`^([0-9]+)\s*(秒|分钟|小时|天)前$`)
+
+	// zhihuLocation 是知乎页面上显示时间用的时区，即北京时间
+	zhihuLocation = time.FixedZone("CST", 8*60*60)
+)
+
+func (s *Session) validQuestionURL(value string) bool {
+	return s.matchZhihuURL(value, reQuestionPath)
 }
 
-func validCollectionURL(value string) bool {
-	return reCollectionURL.MatchString(value)
+func (s *Session) validCollectionURL(value string) bool {
+	return s.matchZhihuURL(value, reCollectionPath)
 }
 
-func validTopicURL(value string) bool {
-	return reTopicURL.MatchString(value)
+func (s *Session) validTopicURL(value string) bool {
+	return s.matchZhihuURL(value, reTopicPath)
+}
+
+// matchZhihuURL 判断 value 是否是 http(s) 链接，host 是 Session 接受的 host 之一，并且路径匹配 rePath
+func (s *Session) matchZhihuURL(value string, rePath *regexp.Regexp) bool {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	if u.RawQuery != "" || u.Fragment != "" || !s.acceptHost(u.Host) {
+		return false
+	}
+	return rePath.MatchString(u.Path)
 }
 
 func reMatchInt(raw string) int {
@@ -70,12 +92,12 @@ func isEmail(value string) bool {
 	return reIsEmail.MatchString(value)
 }
 
-func newHTTPHeaders(isXhr bool) http.Header {
+func (s *Session) newHTTPHeaders(isXhr bool) http.Header {
 	headers := make(http.Header)
 	headers.Set("Accept", "*/*")
 	headers.Set("Connection", "keep-alive")
-	headers.Set("Host", "www.zhihu.com")
-	headers.Set("Origin", "http://www.zhihu.com")
+	headers.Set("Host", s.baseURL.Host)
+	headers.Set("Origin", s.baseURL.Scheme+"://"+s.baseURL.Host)
 	headers.Set("Pragma", "no-cache")
 	headers.Set("User-Agent", userAgent)
 	if isXhr {
@@ -88,6 +110,40 @@ func strip(s string) string {
 	return strings.TrimSpace(s)
 }
 
+// parseZhihuTime 解析知乎页面上显示的时间，如“2016-03-09”、“2016-03-09 12:03”、“昨天 12:03”、“12:03”、
+// “3 小时前”、“刚刚”，相对的时间基于 now 计算。结果都是北京时间，无法识别时返回 false
+func parseZhihuTime(text string, now time.Time) (time.Time, bool) {
+	text = strip(text)
+	now = now.In(zhihuLocation)
+
+	if text == "刚刚" {
+		return now.Truncate(time.Minute), true
+	}
+	if m := reTimeDate.FindStringSubmatch(text); m != nil {
+		layout, value := "2006-01-02", m[1]
+		if m[2] != "" {
+			layout, value = "2006-01-02 15:04", m[1]+" "+m[2]
+		}
+		t, err := time.ParseInLocation(layout, value, zhihuLocation)
+		return t, err == nil
+	}
+	if m := reTimeClock.FindStringSubmatch(text); m != nil {
+		hour, _ := strconv.Atoi(m[2])
+		minute, _ := strconv.Atoi(m[3])
+		day := now.Day()
+		if m[1] != "" {
+			day--
+		}
+		return time.Date(now.Year(), now.Month(), day, hour, minute, 0, 0, zhihuLocation), hour < 24 && minute < 60
+	}
+	if m := reTimeElapsed.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"秒": time.Second, "分钟": time.Minute, "小时": time.Hour, "天": 24 * time.Hour}[m[2]]
+		return now.Add(-time.Duration(n) * unit).Truncate(time.Minute), true
+	}
+	return time.Time{}, false
+}
+
 func minInt(a, b int) int {
 	if a > b {
 		return b
@@ -95,14 +151,6 @@ func minInt(a, b int) int {
 	return a
 }
 
-func getCwd() string {
-	cwd, err := os.Getwd()
-	if err != nil {
-		panic("获取 CWD 失败：" + err.Error())
-	}
-	return cwd
-}
-
 func save(filename string, content []byte) error {
 	return ioutil.WriteFile(filename, content, 0666)
 }
@@ -111,47 +159,24 @@ func saveString(filename string, content string) error {
 	return ioutil.WriteFile(filename, []byte(content), 0666)
 }
 
-func openCaptchaFile(filename string) error {
-	logger.Info("调用外部程序渲染验证码……")
-	var args []string
-	switch runtime.GOOS {
-	case "linux":
-		args = []string{"xdg-open", filename}
-	case "darwin":
-		args = []string{"open", filename}
-	case "freebsd":
-		args = []string{"open", filename}
-	case "netbsd":
-		args = []string{"open", filename}
-	case "windows":
-		var (
-			cmd      = "url.dll,FileProtocolHandler"
-			runDll32 = filepath.Join(os.Getenv("SYSTEMROOT"), "System32", "rundll32.exe")
-		)
-		args = []string{runDll32, cmd, filename}
-	default:
-		fmt.Printf("无法确定操作系统，请自行打开验证码 %s 文件，并输入验证码。", filename)
-	}
-
-	logger.Info("Command: %s", strings.Join(args, " "))
-
-	err := exec.Command(args[0], args[1:]...).Run()
-	if err != nil {
-		return err
-	}
+// makeZhihuLink 把站内的路径拼接成完整的链接，基于 Session 的 BaseURL
+func (s *Session) makeZhihuLink(path string) string {
+	return urlJoin(s.baseURL.String(), path)
+}
 
-	return nil
+// makeColumnLink 把专栏的路径拼接成完整的链接，基于 Session 的 ColumnBaseURL
+func (s *Session) makeColumnLink(path string) string {
+	return urlJoin(s.columnBaseURL.String(), path)
 }
 
-func readCaptchaInput() string {
-	var captcha string
-	fmt.Print(color.CyanString("请输入验证码："))
-	fmt.Scanf("%s", &captcha)
-	return captcha
+// makeColumnLink 同 Session.makeColumnLink
+func (c *Client) makeColumnLink(path string) string {
+	return c.session.makeColumnLink(path)
 }
 
-func makeZhihuLink(path string) string {
-	return urlJoin(baseZhihuURL, path)
+// makeZhihuLink 同 Session.makeZhihuLink
+func (c *Client) makeZhihuLink(path string) string {
+	return c.session.makeZhihuLink(path)
 }
 
 func urlJoin(base, path string) string {
@@ -164,63 +189,123 @@ func urlJoin(base, path string) string {
 	return base + "/" + path
 }
 
-// newDocumentFromUrl 会请求给定的 url，并返回一个 goquery.Document 对象用于解析
-func newDocumentFromURL(url string) (*goquery.Document, error) {
-	resp, err := gSession.Get(url)
-	if err != nil {
-		logger.Error("请求 %s 失败：%s", url, err.Error())
-		return nil, err
-	}
-
-	doc, err := goquery.NewDocumentFromResponse(resp)
-	if err != nil {
-		logger.Error("解析页面失败：%s", err.Error())
-	}
-
-	return doc, err
-}
-
-// ZhihuPage 是一个知乎页面，User, Question, Answer, Collection 的公共部分
+// ZhihuPage 是一个知乎页面，User, Question, Answer, Collection 的公共部分。
+// Page 可以在多个 goroutine 里同时使用，页面只会被请求一次
 type Page struct {
 	// Link 是该页面的链接
 	Link string
 
+	// client 是该页面绑定的 Client，所有请求都通过它发出
+	client *Client
+
+	// mu 保护下面的字段，以及 User, Question 等类型里惰性解析的字段
+	mu sync.RWMutex
+
+	// loading 是正在进行的载入，同时调用 DocCtx 的 goroutine 会等待它完成，而不是各自发起请求
+	loading *pageLoad
+
 	// doc 是 HTML document
 	doc *goquery.Document
 
+	// err 是最近一次载入页面时的错误
+	err error
+
 	// fields 是字段缓存，避免重复解析页面
 	fields map[string]interface{}
 }
 
+// pageLoad 是一次页面载入，done 关闭之后 doc 和 err 就是载入的结果
+type pageLoad struct {
+	done chan struct{}
+	doc  *goquery.Document
+	err  error
+}
+
 // newZhihuPage 是 private 的构造器
-func newZhihuPage(link string) *Page {
+func newZhihuPage(client *Client, link string) *Page {
 	return &Page{
 		Link:   link,
+		client: client,
 		fields: make(map[string]interface{}),
 	}
 }
 
-// Doc 用于获取当前问题页面的 HTML document，惰性求值
+// Doc 用于获取当前问题页面的 HTML document，惰性求值。
+// 载入失败时返回一个空的 document，各个 GetXXX 方法因此会返回零值，错误可以通过 Err 获取
 func (page *Page) Doc() *goquery.Document {
-	if page.doc != nil {
-		return page.doc
-	}
-
-	err := page.Refresh()
+	doc, err := page.DocCtx(context.Background())
 	if err != nil {
-		return nil
+		return emptyDocument()
 	}
+	return doc
+}
 
-	return page.doc
+// Err 返回最近一次载入页面时的错误，没有出错则返回 nil。
+// 可以用 errors.Is 判断错误类型，如 ErrNotFound, ErrLoginRequired
+func (page *Page) Err() error {
+	page.mu.RLock()
+	defer page.mu.RUnlock()
+	return page.err
+}
+
+// DocCtx 同 Doc，页面还没有载入时，用 ctx 发起请求，并返回请求的错误。
+// 各个 GetXXX 方法都是从页面里解析数据的，先用 DocCtx 载入页面，后续的调用就不会再发起请求
+func (page *Page) DocCtx(ctx context.Context) (*goquery.Document, error) {
+	return page.load(ctx, false)
 }
 
 // Refresh 会重新载入当前页面，获取最新的数据
-func (page *Page) Refresh() (err error) {
-	page.fields = make(map[string]interface{})    // 清空缓存
-	page.doc, err = newDocumentFromURL(page.Link) // 重载页面
+func (page *Page) Refresh() error {
+	return page.RefreshCtx(context.Background())
+}
+
+// RefreshCtx 同 Refresh，ctx 取消或超时后请求会被中止
+func (page *Page) RefreshCtx(ctx context.Context) error {
+	_, err := page.load(ctx, true)
 	return err
 }
 
+// load 载入页面，refresh 为 false 时如果已经载入过就直接返回。
+// 如果有其他 goroutine 正在载入，则等待它的结果；它因为自己的 ctx 被取消而失败时，重新载入
+func (page *Page) load(ctx context.Context, refresh bool) (*goquery.Document, error) {
+	for {
+		page.mu.Lock()
+		if !refresh && page.doc != nil {
+			doc := page.doc
+			page.mu.Unlock()
+			return doc, nil
+		}
+
+		if call := page.loading; call != nil {
+			page.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.doc, call.err
+		}
+
+		call := &pageLoad{done: make(chan struct{})}
+		page.loading = call
+		page.mu.Unlock()
+
+		call.doc, call.err = page.client.newDocumentFromURL(ctx, page.Link)
+
+		page.mu.Lock()
+		page.fields = make(map[string]interface{}) // 清空缓存
+		page.doc, page.err = call.doc, call.err
+		page.loading = nil
+		page.mu.Unlock()
+		close(call.done)
+
+		return call.doc, call.err
+	}
+}
+
 // GetXsrf 从当前页面内容抓取 xsrf 的值
 func (page *Page) GetXSRF() string {
 	doc := page.Doc()
@@ -234,23 +319,60 @@ func (page *Page) totalPages() int {
 }
 
 func (page *Page) setField(field string, value interface{}) {
+	page.mu.Lock()
+	defer page.mu.Unlock()
 	page.fields[field] = value
 }
 
 func (page *Page) getIntField(field string) (value int, exists bool) {
+	page.mu.RLock()
+	defer page.mu.RUnlock()
 	if got, ok := page.fields[field]; ok {
 		return got.(int), true
 	}
 	return 0, false
 }
 
+func (page *Page) getTimeField(field string) (value time.Time, exists bool) {
+	page.mu.RLock()
+	defer page.mu.RUnlock()
+	if got, ok := page.fields[field]; ok {
+		return got.(time.Time), true
+	}
+	return time.Time{}, false
+}
+
 func (page *Page) getStringField(field string) (value string, exists bool) {
+	page.mu.RLock()
+	defer page.mu.RUnlock()
 	if got, ok := page.fields[field]; ok {
 		return got.(string), true
 	}
 	return "", false
 }
 
+// lazyField 返回 *field，它是零值时用 parse 从页面解析并保存下来，用于 Question.title 这类构造时可能已经知道的字段。
+// parse 会载入页面，所以不能在持有锁的时候调用
+func lazyField[T comparable](page *Page, field *T, parse func() T) T {
+	var zero T
+	page.mu.RLock()
+	value := *field
+	page.mu.RUnlock()
+	if value != zero {
+		return value
+	}
+
+	value = parse()
+	page.mu.Lock()
+	*field = value
+	page.mu.Unlock()
+	return value
+}
+
+func emptyDocument() *goquery.Document {
+	return goquery.NewDocumentFromNode(&html.Node{Type: html.DocumentNode})
+}
+
 func getTotalPages(doc *goquery.Document) int {
 	pager := doc.Find("div.zm-invite-pager")
 	if pager.Size() == 0 {
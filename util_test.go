@@ -1,7 +1,14 @@
 package zhihu
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func Test_validQuestionURL(t *testing.T) {
@@ -9,13 +16,124 @@ func Test_validQuestionURL(t *testing.T) {
 		"https://www.zhihu.com/question/37284137":  true,
 		"http://www.zhihu.com/question/41114729":   true,
 		"https://www.zhihu.com/question/41114729x": false,
-		"https://www.zhihu.com/question/4111472":   false,
+		"https://www.zhihu.com/question/4111472":   true,
+		"https://www.zhihu.com/question/":          false,
 		"https://www.zhihu.com/":                   false,
 	}
 
+	s := NewSession()
 	for value, expectedResult := range ioMap {
-		if validQuestionURL(value) != expectedResult {
+		if s.validQuestionURL(value) != expectedResult {
 			t.Error("validQuestionURL returns error result")
 		}
 	}
 }
+
+func Test_parseZhihuTime(t *testing.T) {
+	now := time.Date(2016, 3, 1, 2, 30, 15, 0, time.UTC) // 北京时间 2016-03-01 10:30:15
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2016, month, day, hour, minute, 0, 0, zhihuLocation)
+	}
+	ioMap := map[string]time.Time{
+		"2016-02-09":       at(2, 9, 0, 0),
+		"2015-12-31 23:59": time.Date(2015, 12, 31, 23, 59, 0, 0, zhihuLocation),
+		"12:03":            at(3, 1, 12, 3),
+		"昨天 12:03":         at(2, 29, 12, 3),
+		"3 小时前":            at(3, 1, 7, 30),
+		"5分钟前":             at(3, 1, 10, 25),
+		"2 天前":             at(2, 28, 10, 30),
+		" 刚刚 ":             at(3, 1, 10, 30),
+	}
+	for text, expected := range ioMap {
+		if got, ok := parseZhihuTime(text, now); !ok || !got.Equal(expected) {
+			t.Errorf("parseZhihuTime(%q) returns %s, %v, want %s", text, got, ok, expected)
+		}
+	}
+
+	for _, text := range []string{"", "上周", "2016/03/01", "25:61"} {
+		if _, ok := parseZhihuTime(text, now); ok {
+			t.Errorf("parseZhihuTime(%q) should fail", text)
+		}
+	}
+}
+
+func Test_SetBaseURL(t *testing.T) {
+	s := NewSession()
+	if err := s.SetBaseURL("http://127.0.0.1:8080/", "zhihu.example.com"); err != nil {
+		t.Fatalf("SetBaseURL returns error: %s", err.Error())
+	}
+
+	if link := s.makeZhihuLink("/question/37284137"); link != "http://127.0.0.1:8080/question/37284137" {
+		t.Errorf("makeZhihuLink returns %s", link)
+	}
+
+	ioMap := map[string]bool{
+		"http://127.0.0.1:8080/question/37284137":  true,
+		"https://zhihu.example.com/topic/19550517": true,
+		"https://www.zhihu.com/question/37284137":  false,
+	}
+	for value, expectedResult := range ioMap {
+		if (s.validQuestionURL(value) || s.validTopicURL(value)) != expectedResult {
+			t.Errorf("valid URL check for %s returns error result", value)
+		}
+	}
+
+	if err := s.SetBaseURL("www.zhihu.com"); err == nil {
+		t.Error("SetBaseURL without scheme should fail")
+	}
+}
+
+func Test_PageConcurrent(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `<html><body><h2 class="zm-item-title">并发</h2><h3 id="zh-question-answer-num" data-num="42"></h3></body></html>`)
+	}))
+	defer server.Close()
+
+	session := NewSession()
+	session.SetBaseURL(server.URL)
+	client := NewClient(session)
+	client.SetLogger(nil)
+	q, err := client.Question(server.URL+"/question/28966220", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if title := q.GetTitle(); title != "并发" {
+				t.Errorf("GetTitle() = %q", title)
+			}
+			if num := q.GetAnswersNum(); num != 42 {
+				t.Errorf("GetAnswersNum() = %d", num)
+			}
+		}()
+	}
+	wg.Wait()
+	if hits != 1 {
+		t.Errorf("page was fetched %d times, expected 1", hits)
+	}
+
+	// Refresh 和各个 GetXXX 同时进行
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			q.RefreshCtx(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			q.GetAnswersNum()
+			q.Err()
+		}()
+	}
+	wg.Wait()
+	if q.Err() != nil || q.GetAnswersNum() != 42 {
+		t.Errorf("after Refresh: err = %v, answers = %d", q.Err(), q.GetAnswersNum())
+	}
+}
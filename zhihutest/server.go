@@ -0,0 +1,1037 @@
+// Package zhihutest 提供一个在进程内运行的假知乎服务器，用于测试使用 zhihu-go 的代码。
+//
+// Server 基于 httptest.Server，模拟了 zhihu-go 用到的页面和接口：用户主页、问题、回答、收藏夹、话题，
+// 翻页的 Ajax 接口、评论，专栏的 JSON 接口（在 /zhuanlan 下），以及登录、验证码。其中的用户、问题、回答等数据都可以在测试里设置：
+//
+//	server := zhihutest.NewServer()
+//	defer server.Close()
+//
+//	server.AddUser(&zhihutest.User{ID: "jixin", Name: "黄继新"})
+//	server.AddQuestion(&zhihutest.Question{
+//		ID:    28966220,
+//		Title: "Python 编程，应该养成哪些好的习惯？",
+//		Answers: []*zhihutest.Answer{
+//			{ID: 42808045, Author: "jixin", Upvote: 120, Content: "<p>写测试</p>"},
+//		},
+//	})
+//
+//	client := server.Client()
+//	question, err := client.Question(server.Link("/question/28966220"), "")
+package zhihutest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DeanThompson/zhihu-go"
+)
+
+const (
+	pageSize           = 20 // 大部分列表每页的数量
+	collectionPageSize = 10 // 收藏夹每页的问题数量
+	commentPageSize    = 30 // 评论每页的数量
+)
+
+// User 是一个用户
+type User struct {
+	// ID 是用户的个性域名，主页是 /people/{ID}
+	ID string
+
+	// Name 是用户名，即 zhihu.User 的 GetUserID
+	Name string
+
+	Bio       string
+	Location  string
+	Business  string
+	Education string
+
+	// Gender 是性别，male 或 female
+	Gender string
+
+	// HashID 是用户的 data-id，获取关注列表时用到，为空时自动生成
+	HashID string
+
+	AgreeNum  int
+	ThanksNum int
+
+	// Followees 是该用户关注的人的 ID
+	Followees []string
+
+	// Followers 是关注该用户的人的 ID
+	Followers []string
+
+	// Topics 是该用户关注的话题的 ID
+	Topics []int
+
+	// FollowedColumns 是该用户关注的专栏的 Slug
+	FollowedColumns []string
+}
+
+// Question 是一个问题，提问者和回答都属于这个问题
+type Question struct {
+	// ID 是问题的 ID，链接是 /question/{ID}
+	ID int
+
+	Title  string
+	Detail string
+
+	// Asker 是提问者的 ID，为空表示匿名提问
+	Asker string
+
+	// Topics 是问题所属的话题的 ID
+	Topics []int
+
+	// Followers 是关注该问题的用户的 ID
+	Followers []string
+
+	VisitTimes  int
+	CommentsNum int
+
+	// Comments 是问题的评论
+	Comments []*Comment
+
+	// Answers 是问题的回答，按顺序显示
+	Answers []*Answer
+
+	// Related 是侧边栏里相关问题的 ID，只显示已经添加的问题
+	Related []int
+
+	// Closed 表示问题已经被关闭
+	Closed bool
+
+	// MergedInto 是问题被合并到的问题的 ID，不为 0 时访问问题会跳转到 /question/{MergedInto}?rr={ID}
+	MergedInto int
+}
+
+// Answer 是一个回答
+type Answer struct {
+	// ID 是回答的 ID，链接是 /question/{问题 ID}/answer/{ID}，同时也用作 data-aid
+	ID int
+
+	// Author 是作者的 ID，为空表示匿名用户
+	Author string
+
+	// Content 是回答的内容，HTML 格式
+	Content string
+
+	Upvote       int
+	CommentsNum  int
+	CollectedNum int
+
+	// CreatedAt 是发布时间，UpdatedAt 是最后编辑的时间，没有编辑过时为零值。
+	// 页面上按北京时间显示到分钟，如“编辑于 2016-03-10 12:03”
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Voters 是点赞的用户的 ID，空字符串表示匿名用户
+	Voters []string
+
+	// Comments 是回答的评论
+	Comments []*Comment
+
+	question *Question
+}
+
+// Collection 是一个收藏夹
+type Collection struct {
+	// ID 是收藏夹的 ID，链接是 /collection/{ID}
+	ID int
+
+	Name string
+
+	// Creator 是创建者的 ID
+	Creator string
+
+	// Followers 是关注该收藏夹的用户的 ID
+	Followers []string
+
+	// Answers 是收藏的回答的 ID，同一个问题下的回答会显示在一起
+	Answers []int
+
+	CommentsNum int
+
+	// Comments 是收藏夹的评论
+	Comments []*Comment
+}
+
+// Comment 是一条评论，在 /r/{answers,questions,favlists}/{ID}/comments 接口里按顺序返回
+type Comment struct {
+	ID int
+
+	// Author 是作者的 ID，为空表示匿名用户，不存在的用户被当作已注销的用户
+	Author string
+
+	// ReplyTo 是回复的用户的 ID，为空表示不是回复
+	ReplyTo string
+
+	// Content 是评论的内容，HTML 格式
+	Content string
+
+	Likes     int
+	CreatedAt time.Time
+
+	// Deleted 表示评论已被删除，接口不会返回它的内容
+	Deleted bool
+}
+
+// Column 是一个专栏，链接是 /zhuanlan/{Slug}
+type Column struct {
+	Slug        string
+	Name        string
+	Description string
+
+	// Creator 是创建者的 ID
+	Creator string
+
+	FollowersNum int
+
+	// Articles 是专栏的文章，最新的在前面
+	Articles []*Article
+}
+
+// Article 是一篇专栏文章
+type Article struct {
+	// ID 是文章的 ID，链接是 /zhuanlan/p/{ID}
+	ID int
+
+	// Author 是作者的 ID，为空时是专栏的创建者
+	Author string
+
+	Title string
+
+	// Content 是文章的内容，HTML 格式
+	Content string
+
+	Likes       int
+	PublishedAt time.Time
+
+	// Topics 是文章的话题的 ID
+	Topics []int
+
+	// Comments 是文章的评论
+	Comments []*Comment
+
+	column *Column
+}
+
+// Topic 是一个话题
+type Topic struct {
+	// ID 是话题的 ID，链接是 /topic/{ID}
+	ID int
+
+	Name         string
+	Description  string
+	FollowersNum int
+
+	// TopAuthors 是最佳回答者的 ID
+	TopAuthors []string
+
+	// Parents 是父话题的 ID，子话题由其他话题的 Parents 得到，按 ID 排序
+	Parents []int
+}
+
+// Server 是一个假的知乎服务器，数据用 AddUser, AddQuestion 等方法设置
+type Server struct {
+	*httptest.Server
+
+	// Account, Password 和 Captcha 是登录时需要的账号、密码和验证码，
+	// 默认是 test@example.com, p@ssw0rd 和 abcd
+	Account  string
+	Password string
+	Captcha  string
+
+	// RequireLogin 为 true 时，没有登录就访问页面和接口会跳转到 /?next=...，同真实的知乎一样
+	RequireLogin bool
+
+	mu          sync.Mutex
+	xsrf        string
+	tokens      map[string]bool // 已经登录的 z_c0
+	failures    map[string]int  // path -> 状态码
+	users       map[string]*User
+	questions   map[int]*Question
+	questionIDs []int
+	answers     map[int]*Answer
+	collections map[int]*Collection
+	topics      map[int]*Topic
+	columns     map[string]*Column
+	columnSlugs []string
+	articles    map[int]*Article
+	captchaGIF  []byte
+}
+
+// NewServer 创建并启动一个 Server，用完后需要调用 Close
+func NewServer() *Server {
+	s := &Server{
+		Account:     "test@example.com",
+		Password:    "p@ssw0rd",
+		Captcha:     "abcd",
+		xsrf:        randomHex(16),
+		tokens:      make(map[string]bool),
+		failures:    make(map[string]int),
+		users:       make(map[string]*User),
+		questions:   make(map[int]*Question),
+		answers:     make(map[int]*Answer),
+		collections: make(map[int]*Collection),
+		topics:      make(map[int]*Topic),
+		columns:     make(map[string]*Column),
+		articles:    make(map[int]*Article),
+		captchaGIF:  newCaptchaGIF(),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Link 返回 path 在该服务器上的完整链接
+func (s *Server) Link(path string) string {
+	return s.URL + "/" + strings.TrimLeft(path, "/")
+}
+
+// Session 返回一个指向该服务器的 zhihu.Session，专栏的地址是 /zhuanlan。cookies 只保存在内存中，验证码会自动填写
+func (s *Server) Session() *zhihu.Session {
+	session, _ := zhihu.NewSessionWithCookieStore(nil)
+	session.SetBaseURL(s.URL)
+	session.SetColumnBaseURL(s.URL + "/zhuanlan")
+	session.SetCaptchaSolver(zhihu.CaptchaSolverFunc(func([]byte) (string, error) {
+		return s.Captcha, nil
+	}))
+	return session
+}
+
+// Client 返回一个使用 Session() 的 zhihu.Client
+func (s *Server) Client() *zhihu.Client {
+	return zhihu.NewClient(s.Session())
+}
+
+// AddUser 添加用户，ID 相同的用户会被替换
+func (s *Server) AddUser(users ...*User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range users {
+		if u.HashID == "" {
+			u.HashID = randomHex(16)
+		}
+		s.users[u.ID] = u
+	}
+}
+
+// AddQuestion 添加问题及其回答，ID 相同的问题会被替换
+func (s *Server) AddQuestion(questions ...*Question) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range questions {
+		if _, ok := s.questions[q.ID]; !ok {
+			s.questionIDs = append(s.questionIDs, q.ID)
+		}
+		s.questions[q.ID] = q
+		for _, a := range q.Answers {
+			a.question = q
+			s.answers[a.ID] = a
+		}
+	}
+}
+
+// AddCollection 添加收藏夹，收藏的回答需要先用 AddQuestion 添加
+func (s *Server) AddCollection(collections ...*Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range collections {
+		s.collections[c.ID] = c
+	}
+}
+
+// AddTopic 添加话题
+func (s *Server) AddTopic(topics ...*Topic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		s.topics[t.ID] = t
+	}
+}
+
+// AddColumn 添加专栏及其文章，Slug 相同的专栏会被替换
+func (s *Server) AddColumn(columns ...*Column) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range columns {
+		if _, ok := s.columns[c.Slug]; !ok {
+			s.columnSlugs = append(s.columnSlugs, c.Slug)
+		}
+		s.columns[c.Slug] = c
+		for _, a := range c.Articles {
+			a.column = c
+			if a.Author == "" {
+				a.Author = c.Creator
+			}
+			s.articles[a.ID] = a
+		}
+	}
+}
+
+// Fail 让之后对 path（不含查询参数）的请求都返回 status，如 404、429、500，用于测试错误处理。
+// status 为 0 时恢复正常
+func (s *Server) Fail(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == 0 {
+		delete(s.failures, path)
+	} else {
+		s.failures[path] = status
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status, ok := s.failures[r.URL.Path]; ok {
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: "_xsrf", Value: s.xsrf, Path: "/"})
+
+	path := r.URL.Path
+	switch {
+	case path == "/":
+		s.render(w, "home", nil)
+		return
+	case path == "/captcha.gif":
+		w.Header().Set("Content-Type", "image/gif")
+		w.Write(s.captchaGIF)
+		return
+	case strings.HasPrefix(path, "/login/"):
+		s.serveLogin(w, r)
+		return
+	}
+
+	if (s.RequireLogin || path == "/settings/profile") && !s.loggedIn(r) {
+		http.Redirect(w, r, "/?next="+url.QueryEscape(path), http.StatusFound)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch parts[0] {
+	case "settings":
+		s.render(w, "home", nil)
+	case "people":
+		s.servePeople(w, r, parts[1:])
+	case "question":
+		s.serveQuestion(w, r, parts[1:])
+	case "collection":
+		s.serveCollection(w, r, parts[1:])
+	case "topic":
+		s.serveTopic(w, r, parts[1:])
+	case "node":
+		s.serveNode(w, r, parts[1:])
+	case "r":
+		s.serveComments(w, r, parts[1:])
+	case "zhuanlan":
+		s.serveZhuanlan(w, r, parts[1:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) loggedIn(r *http.Request) bool {
+	cookie, err := r.Cookie("z_c0")
+	return err == nil && s.tokens[cookie.Value]
+}
+
+// loginResult 同知乎登录接口返回的 JSON
+type loginResult struct {
+	R         int    `json:"r"`
+	Msg       string `json:"msg"`
+	ErrorCode int    `json:"errcode,omitempty"`
+}
+
+func (s *Server) serveLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	r.ParseForm()
+
+	account := r.PostForm.Get("email")
+	if account == "" {
+		account = r.PostForm.Get("phone_num")
+	}
+
+	result := loginResult{R: 1}
+	switch {
+	case r.PostForm.Get("_xsrf") != s.xsrf:
+		result.Msg, result.ErrorCode = "请求参数异常，请升级客户端后重试", 100002
+	case r.PostForm.Get("captcha") != s.Captcha:
+		result.Msg, result.ErrorCode = "验证码错误", 1991829
+	case account != s.Account || r.PostForm.Get("password") != s.Password:
+		result.Msg, result.ErrorCode = "帐号或密码错误", 100005
+	default:
+		token := randomHex(16)
+		s.tokens[token] = true
+		http.SetCookie(w, &http.Cookie{Name: "z_c0", Value: token, Path: "/", MaxAge: 30 * 24 * 3600})
+		result = loginResult{R: 0, Msg: "登录成功"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// NewLoginToken 返回一个已经登录的 z_c0 cookie 的值，用于测试导入 cookies，如 Session.ImportCookieHeader("z_c0=" + token)
+func (s *Server) NewLoginToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := randomHex(16)
+	s.tokens[token] = true
+	return token
+}
+
+func (s *Server) servePeople(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	user, ok := s.users[parts[0]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	tab := ""
+	if len(parts) > 1 {
+		tab = parts[1]
+	}
+	page := pageParam(r)
+
+	switch tab {
+	case "":
+		s.render(w, "people", s.profileView(user))
+	case "asks":
+		asks := s.questionsAskedBy(user.ID)
+		items := make([]questionView, 0, pageSize)
+		for _, q := range paginate(len(asks), page, pageSize) {
+			items = append(items, s.questionView(asks[q]))
+		}
+		s.render(w, "asks", items)
+	case "answers":
+		answers := s.answersBy(user.ID)
+		items := make([]answerView, 0, pageSize)
+		for _, i := range paginate(len(answers), page, pageSize) {
+			items = append(items, s.answerView(answers[i]))
+		}
+		s.render(w, "answers", items)
+	case "collections":
+		collections := s.collectionsBy(user.ID)
+		view := collectionListView{Pager: newPager(len(collections), page, pageSize)}
+		for _, i := range paginate(len(collections), page, pageSize) {
+			view.Items = append(view.Items, s.collectionView(collections[i]))
+		}
+		s.render(w, "collections", view)
+	case "posts":
+		articles := s.articlesBy(user.ID)
+		items := make([]articleView, 0, pageSize)
+		for _, i := range paginate(len(articles), page, pageSize) {
+			a := articles[i]
+			items = append(items, articleView{Article: a, Link: s.Link("/zhuanlan/p/" + strconv.Itoa(a.ID))})
+		}
+		s.render(w, "posts", items)
+	case "topics":
+		var items []string
+		for _, id := range user.Topics {
+			items = append(items, s.renderString("topic-item", s.topicView(id)))
+		}
+		s.serveNormalAjax(w, r, items)
+	case "columns":
+		if len(parts) != 3 || parts[2] != "followed" {
+			http.NotFound(w, r)
+			return
+		}
+		var items []string
+		for _, slug := range user.FollowedColumns {
+			items = append(items, s.renderString("column-item", s.columnView(slug)))
+		}
+		s.serveNormalAjax(w, r, items)
+	case "followees", "followers":
+		s.render(w, "home", nil)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveQuestion(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	id, _ := strconv.Atoi(parts[0])
+	q, ok := s.questions[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if q.MergedInto != 0 && len(parts) == 1 {
+		http.Redirect(w, r, "/question/"+strconv.Itoa(q.MergedInto)+"?rr="+strconv.Itoa(q.ID), http.StatusFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		view := s.questionView(q)
+		for _, i := range paginate(len(q.Answers), 1, pageSize) {
+			view.Answers = append(view.Answers, s.answerView(q.Answers[i]))
+		}
+		s.render(w, "question", view)
+	case len(parts) == 2 && parts[1] == "followers":
+		s.serveNormalAjax(w, r, s.renderUserCards(q.Followers))
+	case len(parts) == 3 && parts[1] == "answer":
+		aid, _ := strconv.Atoi(parts[2])
+		a, ok := s.answers[aid]
+		if !ok || a.question != q {
+			http.NotFound(w, r)
+			return
+		}
+		s.render(w, "answer", s.answerView(a))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveCollection(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	id, _ := strconv.Atoi(parts[0])
+	c, ok := s.collections[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "followers" {
+		s.serveNormalAjax(w, r, s.renderUserCards(c.Followers))
+		return
+	}
+	if len(parts) > 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	// 同一个问题下的回答显示在一起，每页 10 个问题
+	var groups [][]*Answer
+	index := make(map[*Question]int)
+	for _, aid := range c.Answers {
+		a, ok := s.answers[aid]
+		if !ok {
+			continue
+		}
+		if i, ok := index[a.question]; ok {
+			groups[i] = append(groups[i], a)
+		} else {
+			index[a.question] = len(groups)
+			groups = append(groups, []*Answer{a})
+		}
+	}
+
+	page := pageParam(r)
+	view := s.collectionView(c)
+	view.Pager = newPager(len(groups), page, collectionPageSize)
+	for _, i := range paginate(len(groups), page, collectionPageSize) {
+		for j, a := range groups[i] {
+			item := s.answerView(a)
+			item.ShowQuestion = j == 0
+			view.Answers = append(view.Answers, item)
+		}
+	}
+	s.render(w, "collection", view)
+}
+
+func (s *Server) serveTopic(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || len(parts) > 2 || (len(parts) == 2 && parts[1] != "organize") {
+		http.NotFound(w, r)
+		return
+	}
+	id, _ := strconv.Atoi(parts[0])
+	t, ok := s.topics[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 2 {
+		view := s.topicView(id)
+		for _, parent := range t.Parents {
+			view.Parents = append(view.Parents, s.topicView(parent))
+		}
+		for _, child := range s.childTopics(id) {
+			view.Children = append(view.Children, s.topicView(child))
+		}
+		s.render(w, "topic-organize", view)
+		return
+	}
+
+	view := s.topicView(id)
+	view.Description = t.Description
+	view.FollowersNum = t.FollowersNum
+	view.TopAuthors = s.userViews(t.TopAuthors)
+	s.render(w, "topic", view)
+}
+
+// nodeParams 是 /node/XXX 接口的 params 参数
+type nodeParams struct {
+	URLToken int    `json:"url_token"`
+	PageSize int    `json:"pagesize"`
+	Offset   int    `json:"offset"`
+	HashID   string `json:"hash_id"`
+	AnswerID string `json:"answer_id"`
+}
+
+func (s *Server) serveNode(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) != 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	var params nodeParams
+	if err := json.Unmarshal([]byte(r.FormValue("params")), &params); err != nil {
+		http.Error(w, "params 格式不正确", http.StatusBadRequest)
+		return
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = pageSize
+	}
+
+	switch parts[0] {
+	case "QuestionAnswerListV2":
+		q, ok := s.questions[params.URLToken]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		items := []string{}
+		for _, i := range slice(len(q.Answers), params.Offset, params.PageSize) {
+			items = append(items, s.renderString("answer-item", s.answerView(q.Answers[i])))
+		}
+		s.serveNodeList(w, items)
+	case "ProfileFolloweesListV2", "ProfileFollowersListV2":
+		var user *User
+		for _, u := range s.users {
+			if u.HashID == params.HashID {
+				user = u
+			}
+		}
+		if user == nil {
+			http.NotFound(w, r)
+			return
+		}
+		ids := user.Followees
+		if parts[0] == "ProfileFollowersListV2" {
+			ids = user.Followers
+		}
+		items := []string{}
+		for _, i := range slice(len(ids), params.Offset, pageSize) {
+			items = append(items, s.renderString("user-card", s.userView(ids[i])))
+		}
+		s.serveNodeList(w, items)
+	case "AnswerFullVoteInfoV2":
+		aid, _ := strconv.Atoi(params.AnswerID)
+		a, ok := s.answers[aid]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		s.render(w, "voters", s.userViews(a.Voters))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveComments 返回 /r/{answers,questions,favlists}/{ID}/comments?page=N 的 JSON
+func (s *Server) serveComments(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) != 3 || parts[2] != "comments" {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, _ := strconv.Atoi(parts[1])
+	var comments []*Comment
+	found := false
+	switch parts[0] {
+	case "answers":
+		var a *Answer
+		a, found = s.answers[id]
+		if found {
+			comments = a.Comments
+		}
+	case "questions":
+		var q *Question
+		q, found = s.questions[id]
+		if found {
+			comments = q.Comments
+		}
+	case "favlists":
+		var c *Collection
+		c, found = s.collections[id]
+		if found {
+			comments = c.Comments
+		}
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := pageParam(r)
+	data := []map[string]interface{}{}
+	for _, i := range paginate(len(comments), page, commentPageSize) {
+		data = append(data, s.commentData(comments[i]))
+	}
+
+	s.serveJSON(w, map[string]interface{}{
+		"paging": map[string]int{"perPage": commentPageSize, "totalCount": len(comments), "currentPage": page},
+		"data":   data,
+	})
+}
+
+// commentData 返回评论接口里的一条评论，专栏文章的评论也是这个格式
+func (s *Server) commentData(c *Comment) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":            c.ID,
+		"content":       c.Content,
+		"createdTime":   c.CreatedAt.Format(time.RFC3339),
+		"likesCount":    c.Likes,
+		"isDelete":      c.Deleted,
+		"author":        s.commentAuthor(c.Author),
+		"inReplyToUser": nil,
+	}
+	if c.Deleted {
+		item["content"] = ""
+	}
+	if c.ReplyTo != "" {
+		item["inReplyToUser"] = s.commentAuthor(c.ReplyTo)
+	}
+	return item
+}
+
+// serveZhuanlan 返回专栏的 JSON 接口：/zhuanlan/api/columns/{slug}[/posts] 和 /zhuanlan/api/posts/{id}[/comments]，
+// 列表接口用 limit 和 offset 分页
+func (s *Server) serveZhuanlan(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 3 || len(parts) > 4 || parts[0] != "api" {
+		http.NotFound(w, r)
+		return
+	}
+	sub := ""
+	if len(parts) == 4 {
+		sub = parts[3]
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = pageSize
+	}
+
+	switch parts[1] {
+	case "columns":
+		c, ok := s.columns[parts[2]]
+		switch {
+		case !ok:
+			http.NotFound(w, r)
+		case sub == "":
+			s.serveJSON(w, s.columnData(c))
+		case sub == "posts":
+			data := []map[string]interface{}{}
+			for _, i := range slice(len(c.Articles), offset, limit) {
+				data = append(data, s.articleData(c.Articles[i]))
+			}
+			s.serveJSON(w, data)
+		default:
+			http.NotFound(w, r)
+		}
+	case "posts":
+		id, _ := strconv.Atoi(parts[2])
+		a, ok := s.articles[id]
+		switch {
+		case !ok:
+			http.NotFound(w, r)
+		case sub == "":
+			s.serveJSON(w, s.articleData(a))
+		case sub == "comments":
+			data := []map[string]interface{}{}
+			for _, i := range slice(len(a.Comments), offset, limit) {
+				data = append(data, s.commentData(a.Comments[i]))
+			}
+			s.serveJSON(w, data)
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) columnData(c *Column) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           c.Name,
+		"slug":           c.Slug,
+		"description":    c.Description,
+		"followersCount": c.FollowersNum,
+		"postsCount":     len(c.Articles),
+		"creator":        s.commentAuthor(c.Creator),
+	}
+}
+
+func (s *Server) articleData(a *Article) map[string]interface{} {
+	topics := []map[string]interface{}{}
+	for _, id := range a.Topics {
+		topics = append(topics, map[string]interface{}{"id": id, "name": s.topicView(id).Name})
+	}
+	return map[string]interface{}{
+		"slug":          a.ID,
+		"title":         a.Title,
+		"content":       a.Content,
+		"publishedTime": a.PublishedAt.Format(time.RFC3339),
+		"likesCount":    a.Likes,
+		"commentsCount": len(a.Comments),
+		"url":           "/p/" + strconv.Itoa(a.ID),
+		"author":        s.commentAuthor(a.Author),
+		"column":        map[string]string{"name": a.column.Name, "slug": a.column.Slug},
+		"topics":        topics,
+	}
+}
+
+func (s *Server) serveJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// commentAuthor 返回评论、专栏接口里的用户，userID 为空表示匿名用户，不存在的用户返回 nil，即已注销的用户
+func (s *Server) commentAuthor(userID string) interface{} {
+	if userID == "" {
+		return map[string]interface{}{"name": "匿名用户", "slug": "", "isAnonymous": true}
+	}
+	u, ok := s.users[userID]
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{"name": u.Name, "slug": u.ID, "isAnonymous": false}
+}
+
+// serveNodeList 返回 /node/XXListV2 格式的 JSON：{"r": 0, "msg": ["<html>", ...]}
+func (s *Server) serveNodeList(w http.ResponseWriter, items []string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"r": 0, "msg": items})
+}
+
+// serveNormalAjax 按 offset 分页，返回页面内 Ajax 请求的 JSON：{"r": 0, "msg": [数量, "<html>"]}
+func (s *Server) serveNormalAjax(w http.ResponseWriter, r *http.Request, items []string) {
+	offset, _ := strconv.Atoi(r.FormValue("offset"))
+	indexes := slice(len(items), offset, pageSize)
+
+	var html bytes.Buffer
+	for _, i := range indexes {
+		html.WriteString(items[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"r": 0, "msg": []interface{}{len(indexes), html.String()}})
+}
+
+func (s *Server) questionsAskedBy(userID string) []*Question {
+	var questions []*Question
+	for _, id := range s.questionIDs {
+		if q := s.questions[id]; q.Asker == userID {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+func (s *Server) answersBy(userID string) []*Answer {
+	var answers []*Answer
+	for _, id := range s.questionIDs {
+		for _, a := range s.questions[id].Answers {
+			if a.Author == userID {
+				answers = append(answers, a)
+			}
+		}
+	}
+	return answers
+}
+
+func (s *Server) articlesBy(userID string) []*Article {
+	var articles []*Article
+	for _, slug := range s.columnSlugs {
+		for _, a := range s.columns[slug].Articles {
+			if a.Author == userID {
+				articles = append(articles, a)
+			}
+		}
+	}
+	return articles
+}
+
+func (s *Server) collectionsBy(userID string) []*Collection {
+	var collections []*Collection
+	for _, c := range s.collections {
+		if c.Creator == userID {
+			collections = append(collections, c)
+		}
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].ID < collections[j].ID })
+	return collections
+}
+
+// pageParam 返回查询参数 page 的值，默认是 1
+func pageParam(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+// paginate 返回第 page 页（从 1 开始）的元素下标
+func paginate(total, page, size int) []int {
+	return slice(total, (page-1)*size, size)
+}
+
+// slice 返回从 offset 开始的最多 limit 个元素下标
+func slice(total, offset, limit int) []int {
+	var indexes []int
+	for i := offset; i < total && i < offset+limit; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newCaptchaGIF 生成一张简单的验证码图片
+func newCaptchaGIF() []byte {
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, 16, 8), palette)
+	for x := 0; x < 16; x += 2 {
+		img.SetColorIndex(x, x%8, 1)
+	}
+	var buf bytes.Buffer
+	gif.Encode(&buf, img, nil)
+	return buf.Bytes()
+}
@@ -0,0 +1,364 @@
+package zhihutest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DeanThompson/zhihu-go"
+)
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.AddUser(
+		&User{ID: "jixin", Name: "黄继新", Bio: "和知乎在一起", Location: "北京", Gender: "male",
+			AgreeNum: 68200, Followees: []string{"zhouyuan", "zhang-jia-wei"}, Followers: []string{"zhouyuan"}, Topics: []int{19552832}},
+		&User{ID: "zhouyuan", Name: "周源", Bio: "知乎 CEO", Followers: []string{"jixin"}},
+		&User{ID: "zhang-jia-wei", Name: "张佳玮", Bio: "写字的"},
+	)
+
+	answers := make([]*Answer, 0, 25)
+	for i := 0; i < 25; i++ {
+		answers = append(answers, &Answer{ID: 88475539 + i, Author: "zhang-jia-wei", Upvote: 100 - i, Content: fmt.Sprintf("<p>回答 %d</p>", i)})
+	}
+	answers[0].Author = "jixin"
+	answers[0].Voters = []string{"zhouyuan", "", "zhang-jia-wei"}
+	answers[0].CreatedAt = time.Date(2016, 3, 9, 12, 0, 0, 0, time.UTC)
+	answers[0].UpdatedAt = time.Date(2016, 3, 10, 4, 30, 0, 0, time.UTC)
+	answers[1].Author = ""
+	s.AddQuestion(&Question{
+		ID:        41171543,
+		Title:     "如何评价第一局比赛 AlphaGo 战胜李世石？",
+		Asker:     "jixin",
+		Topics:    []int{19552832},
+		Followers: []string{"zhouyuan", "zhang-jia-wei"},
+		Answers:   answers,
+		Related:   []int{19550000},
+	})
+	s.AddQuestion(
+		&Question{ID: 19550000, Title: "AlphaGo 会赢吗？", Closed: true},
+		&Question{ID: 28966220, Title: "AlphaGo 能赢李世石吗？", MergedInto: 19550000},
+	)
+	s.AddCollection(&Collection{ID: 19653044, Name: "好文", Creator: "jixin", Followers: []string{"zhouyuan"}, Answers: []int{88475539, 88475540}})
+	s.AddTopic(
+		&Topic{ID: 19552832, Name: "Python", Description: "一种编程语言", FollowersNum: 82155, TopAuthors: []string{"zhouyuan"}, Parents: []int{19552330}},
+		&Topic{ID: 19552330, Name: "编程语言"},
+		&Topic{ID: 19556233, Name: "Django", Parents: []int{19552832}},
+		&Topic{ID: 19559424, Name: "网络爬虫", Parents: []int{19552832}},
+		&Topic{ID: 19600426, Name: "Scrapy", Parents: []int{19552832, 19559424}},
+	)
+	return s
+}
+
+func Test_Question(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	q, err := s.Client().Question(s.Link("/question/41171543"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.GetTitle(); got != "如何评价第一局比赛 AlphaGo 战胜李世石？" {
+		t.Errorf("GetTitle() = %q", got)
+	}
+	if got := q.GetFollowersNum(); got != 2 {
+		t.Errorf("GetFollowersNum() = %d, want 2", got)
+	}
+	if got := len(q.GetFollowers()); got != 2 {
+		t.Errorf("len(GetFollowers()) = %d, want 2", got)
+	}
+	if topics := q.GetTopics(); len(topics) != 1 || topics[0].GetName() != "Python" {
+		t.Errorf("GetTopics() = %v", topics)
+	}
+
+	answers := q.GetAllAnswers()
+	if len(answers) != 25 {
+		t.Fatalf("len(GetAllAnswers()) = %d, want 25", len(answers))
+	}
+	if got := answers[0].GetAuthor().GetUserID(); got != "黄继新" {
+		t.Errorf("author = %q, want 黄继新", got)
+	}
+	if got := answers[0].GetCreatedTime(); !got.Equal(time.Date(2016, 3, 9, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetCreatedTime() = %s", got)
+	}
+	if got := answers[0].GetUpdatedTime(); !got.Equal(time.Date(2016, 3, 10, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("GetUpdatedTime() = %s", got)
+	}
+	if !answers[1].GetAuthor().IsAnonymous() {
+		t.Errorf("answers[1] should be anonymous")
+	}
+	if got := answers[24].GetUpvote(); got != 76 {
+		t.Errorf("answers[24].GetUpvote() = %d, want 76", got)
+	}
+
+	related := q.GetRelatedQuestions()
+	if len(related) != 1 || related[0].GetTitle() != "AlphaGo 会赢吗？" || q.IsClosed() || !related[0].IsClosed() {
+		t.Errorf("GetRelatedQuestions() = %v", related)
+	}
+	merged, _ := s.Client().Question(s.Link("/question/28966220"), "")
+	if target := merged.GetRedirectTarget(); target == nil || target.Link != s.Link("/question/19550000") {
+		t.Errorf("GetRedirectTarget() = %v", target)
+	}
+
+	voters := answers[0].GetVoters()
+	if len(voters) != 3 || voters[0].GetUserID() != "周源" || !voters[1].IsAnonymous() {
+		t.Errorf("GetVoters() = %v", voters)
+	}
+}
+
+func Test_ConcurrentAnswers(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	answers := make([]*Answer, 0, 75)
+	for i := 0; i < 75; i++ {
+		answers = append(answers, &Answer{ID: 90000000 + i, Author: "zhouyuan", Upvote: 1000 - i, Content: fmt.Sprintf("<p>%d</p>", i)})
+	}
+	s.AddQuestion(&Question{ID: 41171544, Title: "回答很多的问题", Asker: "jixin", Answers: answers})
+
+	client := s.Client()
+	client.Session().SetConcurrency(3)
+	q, _ := client.Question(s.Link("/question/41171544"), "")
+	got, err := q.GetAllAnswersCtx(context.Background())
+	if err != nil || len(got) != 75 {
+		t.Fatalf("GetAllAnswersCtx() = %d answers, %v", len(got), err)
+	}
+	for i, answer := range got {
+		if answer.GetUpvote() != 1000-i {
+			t.Fatalf("answers[%d].GetUpvote() = %d, want %d", i, answer.GetUpvote(), 1000-i)
+		}
+	}
+}
+
+func Test_Comments(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	comments := make([]*Comment, 0, 35)
+	for i := 0; i < 35; i++ {
+		comments = append(comments, &Comment{ID: 1000 + i, Author: "zhouyuan", Content: fmt.Sprintf("<p>%d</p>", i), Likes: i})
+	}
+	comments[1].Author = ""
+	comments[2].Author, comments[2].ReplyTo = "deleted-user", "jixin"
+	comments[3].Deleted = true
+	s.AddCollection(&Collection{ID: 19653045, Name: "评论很多", Creator: "jixin", Comments: comments})
+
+	c, _ := s.Client().Collection(s.Link("/collection/19653045"), "", nil)
+	got, err := c.GetCommentsNCtx(context.Background(), -1)
+	if err != nil || len(got) != 35 {
+		t.Fatalf("GetCommentsNCtx() = %d comments, %v", len(got), err)
+	}
+	if got[0].Author.GetUserID() != "周源" || got[34].Likes != 34 {
+		t.Errorf("got[0] = %+v, got[34] = %+v", got[0], got[34])
+	}
+	if !got[1].Author.IsAnonymous() || got[1].Author.Link != "" {
+		t.Errorf("got[1].Author should be anonymous: %s", got[1].Author)
+	}
+	if got[2].Author.GetUserID() != "知乎用户" || got[2].ReplyTo.GetUserID() != "黄继新" {
+		t.Errorf("got[2] should be a reply from a deleted user: %+v", got[2])
+	}
+	if !got[3].Deleted || got[3].Content != "" {
+		t.Errorf("got[3] should be deleted: %+v", got[3])
+	}
+}
+
+func Test_User(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	user, _ := s.Client().User(s.Link("/people/jixin"), "")
+	if got := user.GetUserID(); got != "黄继新" {
+		t.Errorf("GetUserID() = %q", got)
+	}
+	if got := user.GetLocation(); got != "北京" {
+		t.Errorf("GetLocation() = %q", got)
+	}
+	if got := user.GetAgreeNum(); got != 68200 {
+		t.Errorf("GetAgreeNum() = %d", got)
+	}
+	if got := user.GetAsksNum(); got != 1 {
+		t.Errorf("GetAsksNum() = %d, want 1", got)
+	}
+	if asks := user.GetAsks(); len(asks) != 1 || asks[0].GetAnswersNum() != 25 {
+		t.Errorf("GetAsks() = %v", asks)
+	}
+	if answers := user.GetAnswers(); len(answers) != 1 || answers[0].GetUpvote() != 100 {
+		t.Errorf("GetAnswers() = %v", answers)
+	}
+	if collections := user.GetCollections(); len(collections) != 1 || collections[0].GetName() != "好文" {
+		t.Errorf("GetCollections() = %v", collections)
+	}
+	if topics := user.GetFollowedTopics(); len(topics) != 1 || topics[0].GetName() != "Python" {
+		t.Errorf("GetFollowedTopics() = %v", topics)
+	}
+
+	followees := user.GetFollowees()
+	if len(followees) != 2 || followees[1].GetUserID() != "张佳玮" {
+		t.Fatalf("GetFollowees() = %v", followees)
+	}
+	if got := followees[0].GetFollowersNum(); got != 1 {
+		t.Errorf("followees[0].GetFollowersNum() = %d, want 1", got)
+	}
+}
+
+func Test_CollectionAndTopic(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	client := s.Client()
+
+	c, _ := client.Collection(s.Link("/collection/19653044"), "", nil)
+	if got := c.GetCreator().GetUserID(); got != "黄继新" {
+		t.Errorf("GetCreator() = %q", got)
+	}
+	if got := c.GetQuestionsNum(); got != 1 {
+		t.Errorf("GetQuestionsNum() = %d, want 1", got)
+	}
+	if answers := c.GetAnswers(); len(answers) != 2 {
+		t.Errorf("len(GetAnswers()) = %d, want 2", len(answers))
+	}
+	if got := len(c.GetFollowers()); got != 1 {
+		t.Errorf("len(GetFollowers()) = %d, want 1", got)
+	}
+
+	topic, _ := client.Topic(s.Link("/topic/19552832"), "")
+	if got := topic.GetFollowersNum(); got != 82155 {
+		t.Errorf("GetFollowersNum() = %d", got)
+	}
+	if authors := topic.GetTopAuthors(); len(authors) != 1 || authors[0].GetUserID() != "周源" {
+		t.Errorf("GetTopAuthors() = %v", authors)
+	}
+	if parents := topic.GetParents(); len(parents) != 1 || parents[0].GetName() != "编程语言" {
+		t.Errorf("GetParents() = %v", parents)
+	}
+
+	var names []string
+	err := zhihu.WalkTopicTree(context.Background(), topic, -1, func(topic *zhihu.Topic, depth int) error {
+		names = append(names, fmt.Sprintf("%s:%d", topic.GetName(), depth))
+		return nil
+	})
+	if got := fmt.Sprint(names); err != nil || got != "[Python:0 Django:1 网络爬虫:1 Scrapy:1]" {
+		t.Errorf("WalkTopicTree() = %s, err %v", got, err)
+	}
+}
+
+func Test_ColumnAndArticle(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	articles := make([]*Article, 0, 25)
+	for i := 0; i < 25; i++ {
+		articles = append(articles, &Article{ID: 20761239 - i, Title: fmt.Sprintf("文章 %d", i), Content: "<p>正文</p>", Likes: i})
+	}
+	articles[0].Topics = []int{19552832}
+	articles[0].Comments = []*Comment{{ID: 1, Author: "zhouyuan", Content: "<p>好</p>"}}
+	articles[1].Author = "zhouyuan"
+	s.AddColumn(&Column{Slug: "pythoner", Name: "Python 程序员", Creator: "jixin", FollowersNum: 40813, Articles: articles})
+	s.AddUser(&User{ID: "zhouyuan", Name: "周源", FollowedColumns: []string{"pythoner"}})
+	client := s.Client()
+
+	column, err := client.Column(s.Link("/zhuanlan/pythoner"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if column.GetName() != "Python 程序员" || column.GetFollowersNum() != 40813 || column.GetArticlesNum() != 25 {
+		t.Errorf("Column returns error result: %s", column)
+	}
+	if got := column.GetArticles(); len(got) != 25 || got[24].GetID() != 20761215 {
+		t.Errorf("GetArticles() = %v", got)
+	}
+
+	v, err := client.ParseURL(s.Link("/zhuanlan/p/20761239"))
+	article, ok := v.(*zhihu.Article)
+	if err != nil || !ok {
+		t.Fatalf("ParseURL() = %v, %v", v, err)
+	}
+	if article.GetAuthor().GetUserID() != "黄继新" || article.GetColumn().GetSlug() != "pythoner" {
+		t.Errorf("Article returns error result: %s", article)
+	}
+	if topics := article.GetTopics(); len(topics) != 1 || topics[0].GetName() != "Python" {
+		t.Errorf("GetTopics() = %v", topics)
+	}
+	if comments := article.GetComments(); len(comments) != 1 || comments[0].Author.GetUserID() != "周源" {
+		t.Errorf("GetComments() = %v", comments)
+	}
+
+	user, _ := client.User(s.Link("/people/jixin"), "")
+	if got := user.GetPostsNum(); got != 24 {
+		t.Errorf("GetPostsNum() = %d, want 24", got)
+	}
+	if posts := user.GetPosts(); len(posts) != 24 || posts[1].Link != s.Link("/zhuanlan/p/20761237") {
+		t.Errorf("GetPosts() = %v", posts)
+	}
+
+	user, _ = client.User(s.Link("/people/zhouyuan"), "")
+	if columns := user.GetFollowedColumns(); len(columns) != 1 || columns[0].GetName() != "Python 程序员" {
+		t.Errorf("GetFollowedColumns() = %v", columns)
+	}
+}
+
+func Test_Login(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	s.RequireLogin = true
+
+	client := s.Client()
+	q, _ := client.Question(s.Link("/question/41171543"), "")
+	if _, err := q.DocCtx(context.Background()); !errors.Is(err, zhihu.ErrLoginRequired) {
+		t.Fatalf("before login: err = %v, want ErrLoginRequired", err)
+	}
+
+	dir, err := ioutil.TempDir("", "zhihutest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := filepath.Join(dir, "config.json")
+	content := fmt.Sprintf(`{"account": %q, "password": %q}`, s.Account, s.Password)
+	if err := ioutil.WriteFile(cfg, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	session := client.Session()
+	if err := session.LoadConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Login(); err != nil {
+		t.Fatalf("Login() = %v", err)
+	}
+
+	q, _ = client.Question(s.Link("/question/41171543"), "")
+	if _, err := q.DocCtx(context.Background()); err != nil {
+		t.Errorf("after login: err = %v", err)
+	}
+}
+
+func Test_ImportCookies(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	s.RequireLogin = true
+
+	client := s.Client()
+	if err := client.Session().ImportCookieHeader("z_c0=" + s.NewLoginToken()); err != nil {
+		t.Fatalf("ImportCookieHeader() = %v", err)
+	}
+	q, _ := client.Question(s.Link("/question/41171543"), "")
+	if _, err := q.DocCtx(context.Background()); err != nil {
+		t.Errorf("after import: err = %v", err)
+	}
+}
+
+func Test_Fail(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	s.Fail("/question/41171543", 404)
+	q, _ := s.Client().Question(s.Link("/question/41171543"), "")
+	if _, err := q.DocCtx(context.Background()); !errors.Is(err, zhihu.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,240 @@
+package zhihutest
+
+// templateText 是各个页面的模板，只保留了 zhihu-go 解析时用到的标签，结构同真实的知乎页面一致。
+// 每个模板的数据都是 {"XSRF": _xsrf, "Data": 页面数据}
+const templateText = `
+{{define "header"}}<!DOCTYPE html>
+<html lang="zh-CN">
+<head><meta charset="utf-8"><title>{{.}} - 知乎</title></head>
+<body>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "xsrf"}}<input type="hidden" name="_xsrf" value="{{.}}"/>{{end}}
+
+{{define "pager"}}{{if .Pages}}{{$current := .Current}}
+<div class="zm-invite-pager-wrap border-pager">
+<div class="zm-invite-pager">
+{{range .Pages}}<span>{{if eq . $current}}{{.}}{{else}}<a href="?page={{.}}">{{.}}</a>{{end}}</span>{{end}}
+<span><a href="?page={{$current}}">下一页</a></span>
+</div>
+</div>
+{{end}}{{end}}
+
+{{define "home"}}{{template "header" "首页"}}
+{{template "xsrf" .XSRF}}
+{{template "footer"}}{{end}}
+
+{{define "author-info"}}<div class="zm-item-answer-author-info">{{if .Anonymous}}<span class="name">匿名用户</span>{{else}}<a class="author-link" href="/people/{{.ID}}">{{.Name}}</a>{{if .Bio}}，<span title="{{.Bio}}" class="bio">{{.Bio}}</span>{{end}}{{end}}</div>{{end}}
+
+{{define "answer-date"}}<a class="answer-date-link meta-item" target="_blank" href="/question/{{.Question.ID}}/answer/{{.ID}}"{{if .Edited}} data-tip="s$t$发布于 {{date .CreatedAt}}">编辑于 {{date .UpdatedAt}}{{else}}>发布于 {{date .CreatedAt}}{{end}}</a>{{end}}
+
+{{define "answer-body"}}<div tabindex="-1" class="zm-item-answer{{if .Expanded}} zm-item-expanded{{end}}" data-aid="{{.Answer.ID}}" data-atoken="{{.Answer.ID}}" data-isowner="0">
+<div class="zm-votebar"><button class="up"><span class="count">{{.Answer.Upvote}}</span></button></div>
+<div class="answer-head">{{template "author-info" .Answer.AuthorView}}</div>
+<div class="zm-item-rich-text expandable js-collapse-body"><div class="zm-editable-content clearfix">{{html .Answer.Content}}</div></div>
+<div class="zm-item-meta answer-actions clearfix js-contentActions">
+{{template "answer-date" .Answer}}
+{{if .Expanded}}<a href="#" name="addcomment" class="meta-item toggle-comment js-toggleCommentBox">{{.Answer.CommentsNum}} 条评论</a>{{end}}
+</div>
+</div>{{end}}
+
+{{define "answer-item"}}{{template "answer-body" (dict "Answer" .Data "Expanded" false)}}{{end}}
+
+{{define "question"}}{{$q := .Data}}{{template "header" $q.Title}}
+{{template "xsrf" .XSRF}}
+<meta itemprop="visitsCount" content="{{$q.VisitTimes}}">
+<div class="zu-main-content">
+<div class="zm-tag-editor-labels zg-clear">
+{{range $q.Topics}}<a class="zm-item-tag" href="/topic/{{.ID}}">{{.Name}}</a>
+{{end}}</div>
+<div id="zh-question-title"><h2 class="zm-item-title"><span class="zm-editable-content">{{$q.Title}}</span></h2></div>
+<div id="zh-question-detail" class="zm-item-rich-text"><div class="zm-editable-content">{{$q.Detail}}</div></div>
+{{if $q.Closed}}<div id="zh-question-closed-msg" class="zm-item-meta zg-gray-normal">问题已关闭</div>
+{{end}}<div class="zm-item-meta"><div class="zm-meta-panel"><a href="#" name="addcomment" class="toggle-comment meta-item">{{$q.CommentsNum}} 条评论</a></div></div>
+<h3 data-num="{{$q.AnswersNum}}" id="zh-question-answer-num">{{$q.AnswersNum}} 个回答</h3>
+<div id="zh-question-answer-wrap" data-pagesize="20">
+{{range $q.Answers}}{{template "answer-body" (dict "Answer" . "Expanded" false)}}
+{{end}}</div>
+</div>
+<div class="zu-main-sidebar">
+<div class="zg-gray-normal"><a href="/question/{{$q.ID}}/followers"><strong>{{$q.FollowersNum}}</strong></a>人关注该问题</div>
+{{if $q.RelatedQuestions}}<div class="zm-side-section" id="zh-question-related-questions"><ul>
+{{range $q.RelatedQuestions}}<li><a class="question_link" href="/question/{{.ID}}">{{.Title}}</a></li>
+{{end}}</ul></div>
+{{end}}</div>
+{{template "footer"}}{{end}}
+
+{{define "answer"}}{{$a := .Data}}{{template "header" $a.Question.Title}}
+{{template "xsrf" .XSRF}}
+<div class="zu-main-content">
+<div id="zh-question-title"><h2 class="zm-item-title"><a href="/question/{{$a.Question.ID}}">{{$a.Question.Title}}</a></h2></div>
+<div id="zh-question-answer-wrap">
+{{template "answer-body" (dict "Answer" $a "Expanded" true)}}
+</div>
+</div>
+<div class="zu-main-sidebar">
+<p>被收藏 <a href="/question/{{$a.Question.ID}}/answer/{{$a.ID}}/collections" data-za-l="sidebar_answer_collected_count">{{$a.CollectedNum}}</a> 次</p>
+</div>
+{{template "footer"}}{{end}}
+
+{{define "voters"}}<div class="zh-answer-vote-info">
+<div class="voters zg-gray">
+{{$n := len .Data}}{{range $i, $u := .Data}}<span class="user-block">{{if $u.Anonymous}}匿名用户{{else}}<a href="/people/{{$u.ID}}" class="zg-link" title="{{$u.Name}}">{{$u.Name}}</a>{{end}}{{if notLast $i $n}}、{{end}}</span>
+{{end}}</div>
+</div>
+{{end}}
+
+{{define "people"}}{{$u := .Data}}{{template "header" $u.Name}}
+{{template "xsrf" .XSRF}}
+<div class="zu-main-content">
+<div class="body clearfix">
+<img class="Avatar Avatar--l" src="https://pic1.zhimg.com/{{$u.ID}}_l.jpg" alt="{{$u.Name}}">
+<div class="title-section ellipsis"><span class="name">{{$u.Name}}</span>{{if $u.Bio}}，<span class="bio" title="{{$u.Bio}}">{{$u.Bio}}</span>{{end}}</div>
+<div class="weibo-wrap"><a class="zm-profile-header-user-weibo" href="http://weibo.com/{{$u.ID}}"></a></div>
+{{if $u.Location}}<span class="location item" title="{{$u.Location}}">{{$u.Location}}</span>{{end}}
+{{if $u.Business}}<span class="business item" title="{{$u.Business}}">{{$u.Business}}</span>{{end}}
+{{if $u.Education}}<span class="education item" title="{{$u.Education}}">{{$u.Education}}</span>{{end}}
+<span class="item gender"><i class="icon icon-profile-{{if eq $u.Gender "female"}}female{{else}}male{{end}}"></i></span>
+</div>
+<div class="zm-profile-header-info-list">
+<span class="zm-profile-header-user-agree"><strong>{{$u.AgreeNum}}</strong>赞同</span>
+<span class="zm-profile-header-user-thanks"><strong>{{$u.ThanksNum}}</strong>感谢</span>
+</div>
+<div class="zm-profile-header-op-btns clearfix"><button data-id="{{$u.HashID}}" class="zg-btn zg-btn-follow">关注</button></div>
+<div class="profile-navbar clearfix">
+<a class="item" href="/people/{{$u.ID}}/asks"> 提问 <span class="num">{{$u.AsksNum}}</span></a>
+<a class="item" href="/people/{{$u.ID}}/answers"> 回答 <span class="num">{{$u.AnswersNum}}</span></a>
+<a class="item" href="/people/{{$u.ID}}/posts"> 专栏文章 <span class="num">{{$u.PostsNum}}</span></a>
+<a class="item" href="/people/{{$u.ID}}/collections"> 收藏 <span class="num">{{$u.CollectionsNum}}</span></a>
+<a class="item" href="/people/{{$u.ID}}/logs"> 公共编辑 <span class="num">0</span></a>
+</div>
+</div>
+<div class="zu-main-sidebar">
+<div class="zm-profile-side-following zg-clear">
+<a class="item" href="/people/{{$u.ID}}/followees"><span class="zg-gray-normal">关注了</span><br><strong>{{$u.FolloweesNum}}</strong><label> 人</label></a>
+<a class="item" href="/people/{{$u.ID}}/followers"><span class="zg-gray-normal">关注者</span><br><strong>{{$u.FollowersNum}}</strong><label> 人</label></a>
+</div>
+{{if $u.TopicsNum}}<div class="zm-profile-side-section">
+<div class="zm-profile-side-section-title"><a class="zg-link-litblue" href="/people/{{$u.ID}}/topics"><strong>{{$u.TopicsNum}} 个话题</strong></a></div>
+<div class="zm-profile-side-topics zg-clear"></div>
+</div>{{end}}
+{{if $u.ColumnsNum}}<div class="zm-profile-side-section">
+<div class="zm-profile-side-section-title"><a class="zg-link-litblue" href="/people/{{$u.ID}}/columns/followed"><strong>{{$u.ColumnsNum}} 个专栏</strong></a></div>
+<div class="zm-profile-side-columns zg-clear"></div>
+</div>{{end}}
+</div>
+{{template "footer"}}{{end}}
+
+{{define "asks"}}{{template "header" "提问"}}
+<div id="zh-profile-ask-list">
+{{range .Data}}<div class="zm-profile-section-item zg-clear">
+<span class="zm-profile-vote-count"><div class="zm-profile-vote-num">{{.VisitTimes}}</div><div class="zm-profile-vote-type">浏览</div></span>
+<div class="zm-profile-section-main">
+<h2 class="zm-profile-question"><a class="question_link" target="_blank" href="/question/{{.ID}}">{{.Title}}</a></h2>
+<div class="meta zg-gray">
+<span class="zm-profile-setion-time zg-gray">3 天前</span> <span class="zg-bull">•</span> {{.AnswersNum}} 个回答 <span class="zg-bull">•</span> {{.FollowersNum}} 人关注
+</div>
+</div>
+</div>
+{{end}}</div>
+{{template "footer"}}{{end}}
+
+{{define "answers"}}{{template "header" "回答"}}
+<div id="zh-profile-answer-list">
+{{range .Data}}<div class="zm-item" data-type="Answer">
+<h2><a class="question_link" target="_blank" href="/question/{{.Question.ID}}/answer/{{.ID}}">{{.Question.Title}}</a></h2>
+<div class="zm-item-answer" data-aid="{{.ID}}" data-atoken="{{.ID}}">
+<div class="zm-item-vote"><a class="zm-item-vote-count" href="javascript:;" data-votecount="{{.Upvote}}">{{.Upvote}}</a></div>
+<div class="zm-item-meta answer-actions clearfix">{{template "answer-date" .}}</div>
+</div>
+</div>
+{{end}}</div>
+{{template "footer"}}{{end}}
+
+{{define "posts"}}{{template "header" "专栏文章"}}
+<div class="zm-profile-section-list">
+{{range .Data}}<div class="zm-profile-section-item zg-clear">
+<h2><a class="post-link" target="_blank" href="{{.Link}}">{{.Title}}</a></h2>
+</div>
+{{end}}</div>
+{{template "footer"}}{{end}}
+
+{{define "collections"}}{{template "header" "收藏"}}
+<div class="zm-profile-section-list">
+{{range .Data.Items}}<div class="zm-profile-section-item zg-clear">
+<h2 class="zm-profile-fav-item-title-wrap"><a class="zm-profile-fav-item-title" href="/collection/{{.ID}}">{{.Name}}</a></h2>
+<div class="zm-profile-fav-bio">{{.AnswersNum}} 个答案 • {{.FollowersNum}} 人关注</div>
+</div>
+{{end}}</div>
+{{template "pager" .Data.Pager}}
+{{template "footer"}}{{end}}
+
+{{define "collection"}}{{$c := .Data}}{{template "header" $c.Name}}
+{{template "xsrf" .XSRF}}
+<div class="zu-main-content">
+<h2 class="zm-item-title zm-editable-content" id="zh-fav-head-title">{{$c.Name}}</h2>
+<div id="zh-list-meta-wrap"><a href="#" name="addcomment" class="toggle-comment">{{$c.CommentsNum}} 条评论</a></div>
+<div id="zh-list-answer-wrap">
+{{range $c.Answers}}<div class="zm-item" data-type="Answer">
+{{if .ShowQuestion}}<h2 class="zm-item-title"><a target="_blank" href="/question/{{.Question.ID}}">{{.Question.Title}}</a></h2>
+{{end}}<div tabindex="-1" class="zm-item-fav">
+<div class="zm-item-answer" data-aid="{{.ID}}" data-atoken="{{.ID}}" data-isowner="0">
+<div class="zm-item-vote"><a class="zm-item-vote-count" href="javascript:;" data-votecount="{{.Upvote}}">{{.Upvote}}</a></div>
+<div class="answer-head">{{template "author-info" .AuthorView}}</div>
+<div class="zm-item-rich-text expandable js-collapse-body" data-entry-url="/question/{{.Question.ID}}/answer/{{.ID}}">
+<div class="zh-summary summary clearfix">{{html .Content}}</div>
+</div>
+<div class="zm-item-meta answer-actions clearfix">{{template "answer-date" .}}</div>
+</div>
+</div>
+</div>
+{{end}}</div>
+{{template "pager" $c.Pager}}
+</div>
+<div class="zu-main-sidebar">
+<h2 class="zm-list-content-title"><a href="/people/{{$c.CreatorView.ID}}">{{$c.CreatorView.Name}}</a></h2>
+<a href="/collection/{{$c.ID}}/followers" data-za-a="visit_collection_followers">{{$c.FollowersNum}}</a> 人关注
+</div>
+{{template "footer"}}{{end}}
+
+{{define "topic"}}{{$t := .Data}}{{template "header" $t.Name}}
+{{template "xsrf" .XSRF}}
+<div class="zu-main-content">
+<h1 class="zm-editable-content">{{$t.Name}}</h1>
+<div id="zh-topic-desc"><div class="zm-editable-content">{{$t.Description}}</div></div>
+</div>
+<div class="zu-main-sidebar">
+<div class="zm-topic-side-followers-info"><a href="/topic/{{$t.ID}}/followers"><strong>{{$t.FollowersNum}}</strong></a> 人关注了该话题</div>
+<div class="zm-side-section" id="zh-topic-top-answerer">
+{{range $t.TopAuthors}}<div class="zm-topic-side-person-item">
+<div class="zm-topic-side-person-item-content">
+<a href="/people/{{.ID}}" class="zg-link">{{.Name}}</a>
+<div class="zm-topic-side-bio" title="{{.Bio}}">{{.Bio}}</div>
+</div>
+</div>
+{{end}}</div>
+</div>
+{{template "footer"}}{{end}}
+
+{{define "topic-organize"}}{{$t := .Data}}{{template "header" $t.Name}}
+<div class="zu-main-content">
+<h1 class="zm-editable-content">{{$t.Name}}</h1>
+<div id="zh-topic-organize-parent-editor"><div class="zm-tag-editor-labels zg-clear">
+{{range $t.Parents}}<a class="zm-item-tag" href="/topic/{{.ID}}">{{.Name}}</a>
+{{end}}</div></div>
+<div id="zh-topic-organize-child-editor"><div class="zm-tag-editor-labels zg-clear">
+{{range $t.Children}}<a class="zm-item-tag" href="/topic/{{.ID}}">{{.Name}}</a>
+{{end}}</div></div>
+</div>
+{{template "footer"}}{{end}}
+
+{{define "topic-item"}}{{with .Data}}<div class="zm-profile-section-item zg-clear"><a class="zm-list-avatar-link" href="/topic/{{.ID}}"></a><div class="zm-profile-section-main"><a href="/topic/{{.ID}}"><strong>{{.Name}}</strong></a></div></div>{{end}}{{end}}
+
+{{define "column-item"}}{{with .Data}}<div class="zm-profile-section-item zg-clear"><a class="zm-list-avatar-link" href="{{.Link}}"></a><div class="zm-profile-section-main"><a href="{{.Link}}"><strong>{{.Name}}</strong></a></div></div>{{end}}{{end}}
+
+{{define "user-card"}}{{with .Data}}<div class="zm-profile-card zm-profile-section-item zg-clear no-hovercard"><div class="zm-list-content-medium"><h2 class="zm-list-content-title"><a href="{{.Link}}" class="zg-link" title="{{.Name}}">{{.Name}}</a></h2><div class="zg-big-gray">{{.Bio}}</div><div class="details zg-gray"><a href="/people/{{.ID}}/followers">{{len .Followers}} 关注者</a> / <a href="/people/{{.ID}}/asks">{{.AsksNum}} 提问</a> / <a href="/people/{{.ID}}/answers">{{.AnswersNum}} 回答</a> / <a href="/people/{{.ID}}">{{.AgreeNum}} 赞同</a></div></div></div>{{end}}{{end}}
+`
@@ -0,0 +1,240 @@
+package zhihutest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// 以下是渲染页面用的数据，链接都是站内的路径，只有用户卡片里的链接是完整的，同真实的知乎一样
+
+type userView struct {
+	*User
+	Link       string // 完整链接
+	Anonymous  bool
+	AsksNum    int
+	AnswersNum int
+}
+
+type questionView struct {
+	*Question
+	Topics       []topicView
+	FollowersNum int
+	AnswersNum   int
+	Answers      []answerView
+
+	RelatedQuestions []*Question
+}
+
+type answerView struct {
+	*Answer
+	Question     *Question
+	AuthorView   userView
+	ShowQuestion bool // 收藏夹里同一个问题下的回答，只有第一个显示问题的标题
+}
+
+type collectionView struct {
+	*Collection
+	CreatorView  userView
+	FollowersNum int
+	AnswersNum   int
+	Answers      []answerView
+	Pager        pager
+}
+
+type collectionListView struct {
+	Items []collectionView
+	Pager pager
+}
+
+type topicView struct {
+	ID           int
+	Name         string
+	Description  string
+	FollowersNum int
+	TopAuthors   []userView
+	Parents      []topicView
+	Children     []topicView
+}
+
+type profileView struct {
+	userView
+	PostsNum       int
+	CollectionsNum int
+	FolloweesNum   int
+	FollowersNum   int
+	TopicsNum      int
+	ColumnsNum     int
+}
+
+type articleView struct {
+	*Article
+	Link string // 完整链接
+}
+
+type columnView struct {
+	Slug string
+	Name string
+	Link string // 完整链接，同真实的知乎一样
+}
+
+// pager 是分页信息，只有一页时不显示
+type pager struct {
+	Current int
+	Pages   []int
+}
+
+func newPager(total, current, size int) pager {
+	p := pager{Current: current}
+	for i := 1; (i-1)*size < total; i++ {
+		p.Pages = append(p.Pages, i)
+	}
+	if len(p.Pages) <= 1 {
+		p.Pages = nil
+	}
+	return p
+}
+
+func (s *Server) userView(id string) userView {
+	user, ok := s.users[id]
+	if id == "" || !ok {
+		return userView{User: &User{Name: "匿名用户"}, Anonymous: true}
+	}
+	return userView{
+		User:       user,
+		Link:       s.Link("/people/" + user.ID),
+		AsksNum:    len(s.questionsAskedBy(user.ID)),
+		AnswersNum: len(s.answersBy(user.ID)),
+	}
+}
+
+func (s *Server) userViews(ids []string) []userView {
+	views := make([]userView, 0, len(ids))
+	for _, id := range ids {
+		views = append(views, s.userView(id))
+	}
+	return views
+}
+
+func (s *Server) renderUserCards(ids []string) []string {
+	cards := make([]string, 0, len(ids))
+	for _, view := range s.userViews(ids) {
+		cards = append(cards, s.renderString("user-card", view))
+	}
+	return cards
+}
+
+func (s *Server) profileView(user *User) profileView {
+	return profileView{
+		userView:       s.userView(user.ID),
+		PostsNum:       len(s.articlesBy(user.ID)),
+		CollectionsNum: len(s.collectionsBy(user.ID)),
+		FolloweesNum:   len(user.Followees),
+		FollowersNum:   len(user.Followers),
+		TopicsNum:      len(user.Topics),
+		ColumnsNum:     len(user.FollowedColumns),
+	}
+}
+
+func (s *Server) questionView(q *Question) questionView {
+	view := questionView{
+		Question:     q,
+		FollowersNum: len(q.Followers),
+		AnswersNum:   len(q.Answers),
+	}
+	for _, id := range q.Topics {
+		view.Topics = append(view.Topics, s.topicView(id))
+	}
+	for _, id := range q.Related {
+		if related, ok := s.questions[id]; ok {
+			view.RelatedQuestions = append(view.RelatedQuestions, related)
+		}
+	}
+	return view
+}
+
+// Edited 表示回答编辑过，时间链接显示“编辑于”，发布时间放在 data-tip 里
+func (v answerView) Edited() bool {
+	return v.UpdatedAt.After(v.CreatedAt)
+}
+
+func (s *Server) answerView(a *Answer) answerView {
+	return answerView{
+		Answer:     a,
+		Question:   a.question,
+		AuthorView: s.userView(a.Author),
+	}
+}
+
+func (s *Server) collectionView(c *Collection) collectionView {
+	return collectionView{
+		Collection:   c,
+		CreatorView:  s.userView(c.Creator),
+		FollowersNum: len(c.Followers),
+		AnswersNum:   len(c.Answers),
+	}
+}
+
+func (s *Server) topicView(id int) topicView {
+	if t, ok := s.topics[id]; ok {
+		return topicView{ID: id, Name: t.Name}
+	}
+	return topicView{ID: id, Name: fmt.Sprintf("话题 %d", id)}
+}
+
+// childTopics 返回 Parents 里有 id 的话题的 ID，按 ID 排序
+func (s *Server) childTopics(id int) []int {
+	var children []int
+	for _, t := range s.topics {
+		for _, parent := range t.Parents {
+			if parent == id {
+				children = append(children, t.ID)
+				break
+			}
+		}
+	}
+	sort.Ints(children)
+	return children
+}
+
+func (s *Server) columnView(slug string) columnView {
+	view := columnView{Slug: slug, Name: slug, Link: s.Link("/zhuanlan/" + slug)}
+	if c, ok := s.columns[slug]; ok {
+		view.Name = c.Name
+	}
+	return view
+}
+
+func (s *Server) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(s.renderString(name, data)))
+}
+
+func (s *Server) renderString(name string, data interface{}) string {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, map[string]interface{}{"XSRF": s.xsrf, "Data": data}); err != nil {
+		panic(err) // 模板是固定的，出错说明代码有问题
+	}
+	return buf.String()
+}
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"html": func(s string) template.HTML { return template.HTML(s) },
+	"dict": func(pairs ...interface{}) map[string]interface{} {
+		m := make(map[string]interface{}, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			m[pairs[i].(string)] = pairs[i+1]
+		}
+		return m
+	},
+	"notLast": func(i, n int) bool { return i < n-1 },
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(time.FixedZone("CST", 8*60*60)).Format("2006-01-02 15:04")
+	},
+}).Parse(templateText))